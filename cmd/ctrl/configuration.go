@@ -27,7 +27,9 @@ import (
 	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/healthcheck"
 	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
 	"github.com/banzaicloud/nodepool-labels-operator/pkg/controller"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/debug"
 	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/webhook"
 )
 
 // main configuration
@@ -53,6 +55,12 @@ type Config struct {
 
 	// Labeler configuration
 	Labeler labeler.Config `mapstructure:"labeler"`
+
+	// Webhook configuration
+	Webhook webhook.Config `mapstructure:"webhook"`
+
+	// Debug API configuration
+	DebugAPI debug.Config `mapstructure:"debugAPI"`
 }
 
 // Validate validates the configuration
@@ -67,6 +75,26 @@ func (c Config) Validate() error {
 		return errors.WrapIf(err, "could not validate healthcheck config")
 	}
 
+	err = c.Controller.Validate()
+	if err != nil {
+		return errors.WrapIf(err, "could not validate controller config")
+	}
+
+	err = c.Labeler.Validate()
+	if err != nil {
+		return errors.WrapIf(err, "could not validate labeler config")
+	}
+
+	err = c.Webhook.Validate()
+	if err != nil {
+		return errors.WrapIf(err, "could not validate webhook config")
+	}
+
+	err = c.DebugAPI.Validate()
+	if err != nil {
+		return errors.WrapIf(err, "could not validate debug API config")
+	}
+
 	return nil
 }
 
@@ -85,6 +113,14 @@ func configure() {
 	}
 
 	err = configuration.Validate()
+	if viper.GetBool("validate-config") {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid configuration: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("configuration is valid")
+		os.Exit(0)
+	}
 	if err != nil {
 		panic(errors.WrapIf(err, "cloud not validate configuration"))
 	}
@@ -15,8 +15,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"emperror.dev/emperror"
 	"emperror.dev/errors"
@@ -29,14 +33,17 @@ import (
 	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/healthcheck"
 	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
 	"github.com/banzaicloud/nodepool-labels-operator/pkg/controller"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/debug"
 	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
 	"github.com/banzaicloud/nodepool-labels-operator/pkg/utils"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/webhook"
 )
 
 // nolint: gochecknoinits
 func init() {
 	pflag.Bool("version", false, "Show version information")
 	pflag.Bool("dump-config", false, "Dump configuration to the console")
+	pflag.Bool("validate-config", false, "Validate configuration and exit with a non-zero code on problems")
 }
 
 func main() {
@@ -61,7 +68,7 @@ func main() {
 	}
 
 	// Create logger
-	logger := log.NewLogger(configuration.Log)
+	logger, logLevels := log.NewLoggerWithLevels(configuration.Log)
 
 	// Create error handler
 	errorHandler := errorhandler.ErrorHandler(logger)
@@ -69,25 +76,77 @@ func main() {
 
 	logger.Infof("Starting %s", FriendlyServiceName)
 
-	// Starts health check HTTP server
+	// Root context which is cancelled on SIGTERM or SIGINT
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// Toggles debug logging on SIGUSR1
+	usr1 := make(chan os.Signal, 1)
+	signal.Notify(usr1, syscall.SIGUSR1)
 	go func() {
-		healthcheck.New(configuration.Healthcheck, logger, errorHandler)
+		for {
+			select {
+			case <-usr1:
+				logger.WithField("level", logLevels.ToggleDebug()).Info("log level changed")
+			case <-ctx.Done():
+				signal.Stop(usr1)
+				return
+			}
+		}
 	}()
 
 	k8sconfig, err := utils.GetK8sConfig()
 	emperror.Panic(err)
 
+	if configuration.Controller.Client.QPS > 0 {
+		k8sconfig.QPS = configuration.Controller.Client.QPS
+	}
+	if configuration.Controller.Client.Burst > 0 {
+		k8sconfig.Burst = configuration.Controller.Client.Burst
+	}
+
 	clientset, err := kubernetes.NewForConfig(k8sconfig)
 	emperror.Panic(err)
 
-	nodeLabeler := labeler.New(labeler.Config{
-		ManagedLabelsAnnotation: configuration.Labeler.ManagedLabelsAnnotation,
-		ForbiddenLabelDomains:   configuration.Labeler.ForbiddenLabelDomains,
-	}, clientset, logger, errorHandler)
+	nodeLabeler := labeler.New(configuration.Labeler, clientset, logger.Component("labeler"), errorHandler)
 
-	ctrl, err := controller.New(configuration.Controller, k8sconfig, nodeLabeler, logger, errorHandler)
+	ctrl, err := controller.New(configuration.Controller, k8sconfig, nodeLabeler, logger.Component("controller"), errorHandler)
 	emperror.Panic(err)
 
-	err = ctrl.Start()
-	emperror.Panic(err)
+	// Applies configuration file changes at runtime
+	watchConfig(configuration, logLevels, nodeLabeler, ctrl, logger, errorHandler)
+
+	// Starts health check HTTP server along with the log level and debug endpoints
+	var healthcheckHandlers []healthcheck.Handler
+	if configuration.Log.LevelEndpoint != "" {
+		healthcheckHandlers = append(healthcheckHandlers, logLevels)
+	}
+	if configuration.DebugAPI.Enabled {
+		healthcheckHandlers = append(healthcheckHandlers, debug.New(configuration.DebugAPI, ctrl))
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		healthcheck.New(ctx, configuration.Healthcheck, ctrl, logger.Component("healthcheck"), errorHandler, healthcheckHandlers...)
+	}()
+
+	// Starts admission webhook HTTPS server
+	if configuration.Webhook.Enabled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			webhook.New(configuration.Webhook, ctrl, logger.Component("webhook"), errorHandler).Run(ctx)
+		}()
+	}
+
+	err = ctrl.Start(ctx)
+	if err != nil {
+		stop()
+		emperror.Panic(err)
+	}
+
+	logger.Infof("Stopping %s", FriendlyServiceName)
 }
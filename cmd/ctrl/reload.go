@@ -0,0 +1,125 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/controller"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
+)
+
+// watchConfig applies the changes of the configuration file which are safe to
+// apply at runtime, other changes are rejected until the operator is restarted
+func watchConfig(current Config, logLevels *log.Levels, nodeLabeler *labeler.Labeler, ctrl *controller.Controller, logger log.Logger, errorHandler emperror.Handler) {
+	if viper.ConfigFileUsed() == "" {
+		return
+	}
+
+	viper.OnConfigChange(func(event fsnotify.Event) {
+		logger := logger.WithField("file", event.Name)
+
+		var config Config
+		err := viper.Unmarshal(&config)
+		if err != nil {
+			errorHandler.Handle(errors.WrapIf(err, "could not unmarshal changed configuration"))
+			return
+		}
+
+		err = config.Validate()
+		if err != nil {
+			errorHandler.Handle(errors.WrapIf(err, "could not validate changed configuration"))
+			return
+		}
+
+		if unsafe := unsafeChanges(current, config); len(unsafe) > 0 {
+			logger.WithField("reason", "changing "+strings.Join(unsafe, ", ")+" requires a restart").
+				Warn("rejecting configuration change")
+			return
+		}
+
+		if reflect.DeepEqual(current, config) {
+			return
+		}
+
+		logLevels.UpdateConfig(config.Log)
+
+		reconcile := !reflect.DeepEqual(current.Labeler, config.Labeler) ||
+			!reflect.DeepEqual(current.Controller.NodepoolNameLabels, config.Controller.NodepoolNameLabels)
+		nodeLabeler.UpdateConfig(config.Labeler)
+		ctrl.SetNodepoolNameLabels(config.Controller.NodepoolNameLabels)
+		current = config
+
+		if !reconcile {
+			logger.Info("configuration reloaded")
+			return
+		}
+
+		logger.Info("configuration reloaded, reconciling all nodes")
+		err = ctrl.EnqueueAllNodes()
+		if err != nil {
+			errorHandler.Handle(errors.WrapIf(err, "could not enqueue nodes"))
+		}
+	})
+	viper.WatchConfig()
+}
+
+// unsafeChanges gives back the settings which were changed but can't be applied at runtime
+func unsafeChanges(current Config, config Config) []string {
+	// the managed labels annotation names can only be changed if the records
+	// on the nodes are still read, e.g. the previous name is a legacy one
+	renameAnnotations := config.Labeler.ReadsRecordsOf(current.Labeler)
+
+	// settings which are applied at runtime
+	for _, c := range []*Config{&current, &config} {
+		c.Log.Level, c.Log.Components = "", nil
+		c.Labeler.ForbiddenLabelDomains = nil
+		c.Labeler.OptimisticConcurrency = false
+		c.Controller.NodepoolNameLabels = nil
+		if renameAnnotations {
+			c.Labeler.ManagedLabelsAnnotation = ""
+			c.Labeler.LegacyManagedLabelsAnnotations = nil
+		}
+	}
+
+	return changedSettings(reflect.ValueOf(current), reflect.ValueOf(config))
+}
+
+func changedSettings(a reflect.Value, b reflect.Value, parts ...string) []string {
+	if a.Kind() != reflect.Struct {
+		if reflect.DeepEqual(a.Interface(), b.Interface()) {
+			return nil
+		}
+		return []string{strings.Join(parts, ".")}
+	}
+
+	var changed []string
+	for i := 0; i < a.NumField(); i++ {
+		tv, ok := a.Type().Field(i).Tag.Lookup("mapstructure")
+		if !ok {
+			continue
+		}
+		changed = append(changed, changedSettings(a.Field(i), b.Field(i), append(parts, tv)...)...)
+	}
+
+	return changed
+}
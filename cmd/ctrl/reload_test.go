@@ -0,0 +1,94 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
+)
+
+func TestUnsafeChanges(t *testing.T) {
+	const (
+		defaultAnnotation = "nodepool.banzaicloud.io/managed-labels"
+		newAnnotation     = "example.com/managed-labels"
+	)
+
+	tests := []struct {
+		name    string
+		current labeler.Config
+		config  labeler.Config
+		unsafe  []string
+	}{
+		{
+			name:    "runtime settings",
+			current: labeler.Config{ForbiddenLabelDomains: []string{"example.com"}},
+			config:  labeler.Config{OptimisticConcurrency: true},
+		},
+		{
+			name:    "rename with the previous name as a legacy one",
+			current: labeler.Config{},
+			config: labeler.Config{
+				ManagedLabelsAnnotation:        newAnnotation,
+				LegacyManagedLabelsAnnotations: []string{defaultAnnotation},
+			},
+		},
+		{
+			name: "rename back to a legacy name",
+			current: labeler.Config{
+				ManagedLabelsAnnotation:        newAnnotation,
+				LegacyManagedLabelsAnnotations: []string{defaultAnnotation},
+			},
+			config: labeler.Config{
+				LegacyManagedLabelsAnnotations: []string{newAnnotation},
+			},
+		},
+		{
+			name:    "rename without the previous name as a legacy one",
+			current: labeler.Config{},
+			config:  labeler.Config{ManagedLabelsAnnotation: newAnnotation},
+			unsafe:  []string{"labeler.managedLabelsAnnotation"},
+		},
+		{
+			name: "dropping a legacy name",
+			current: labeler.Config{
+				ManagedLabelsAnnotation:        newAnnotation,
+				LegacyManagedLabelsAnnotations: []string{defaultAnnotation},
+			},
+			config: labeler.Config{
+				ManagedLabelsAnnotation: newAnnotation,
+			},
+			unsafe: []string{"labeler.legacyManagedLabelsAnnotations"},
+		},
+		{
+			name:    "adding a legacy name",
+			current: labeler.Config{},
+			config: labeler.Config{
+				LegacyManagedLabelsAnnotations: []string{newAnnotation},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			unsafe := unsafeChanges(Config{Labeler: test.current}, Config{Labeler: test.config})
+			if !reflect.DeepEqual(unsafe, test.unsafe) {
+				t.Errorf("expected unsafe changes %v, got %v", test.unsafe, unsafe)
+			}
+		})
+	}
+}
@@ -15,10 +15,33 @@ fi
 PROJECT_DIR=$(realpath $(dirname "${BASH_SOURCE}"))
 
 cd ${GOPATH}/src/k8s.io/code-generator
+go install ./cmd/{applyconfiguration-gen,client-gen,lister-gen,informer-gen,deepcopy-gen}
+GOBIN="$(go env GOBIN)"
+GOBIN="${GOBIN:-$(go env GOPATH)/bin}"
 
-./generate-groups.sh \
-  all \
-  github.com/banzaicloud/nodepool-labels-operator/pkg/client \
-  github.com/banzaicloud/nodepool-labels-operator/pkg/apis \
-  nodepoollabelset:v1alpha1 \
-  --go-header-file ${PROJECT_DIR}/header.txt
+MODULE=github.com/banzaicloud/nodepool-labels-operator
+APIS=${MODULE}/pkg/apis/nodepoollabelset/v1alpha1,${MODULE}/pkg/apis/nodepoollabelset/v1beta1
+OUTPUT_PKG=${MODULE}/pkg/client
+HEADER=${PROJECT_DIR}/header.txt
+
+# the external apply configurations need an applyconfiguration-gen which
+# splits the type names at the last dot, the one of v0.21.9 doesn't
+META=k8s.io/apimachinery/pkg/apis/meta/v1
+METAAC=k8s.io/client-go/applyconfigurations/meta/v1
+EXTERNAL_APPLYCONFIGURATIONS=${META}.ObjectMeta:${METAAC},${META}.TypeMeta:${METAAC},${META}.OwnerReference:${METAAC},${META}.ManagedFieldsEntry:${METAAC},${META}.LabelSelector:${METAAC},${META}.LabelSelectorRequirement:${METAAC},${META}.Condition:${METAAC},k8s.io/api/core/v1.Taint:k8s.io/client-go/applyconfigurations/core/v1
+
+${GOBIN}/deepcopy-gen --input-dirs ${APIS} -O zz_generated.deepcopy --bounding-dirs ${MODULE}/pkg/apis --go-header-file ${HEADER}
+
+${GOBIN}/applyconfiguration-gen --input-dirs ${APIS} --external-applyconfigurations ${EXTERNAL_APPLYCONFIGURATIONS} --output-package ${OUTPUT_PKG}/applyconfiguration --go-header-file ${HEADER}
+# applyconfiguration-gen names the directory after the API group while
+# client-gen imports it by the package directory
+APPLYCONFIGURATION_DIR=${GOPATH}/src/${OUTPUT_PKG}/applyconfiguration
+rm -rf ${APPLYCONFIGURATION_DIR}/nodepoollabelset
+mv ${APPLYCONFIGURATION_DIR}/labels ${APPLYCONFIGURATION_DIR}/nodepoollabelset
+sed -i "s#${OUTPUT_PKG}/applyconfiguration/labels/#${OUTPUT_PKG}/applyconfiguration/nodepoollabelset/#" ${APPLYCONFIGURATION_DIR}/utils.go
+
+${GOBIN}/client-gen --clientset-name versioned --input-base "" --input ${APIS} --apply-configuration-package ${OUTPUT_PKG}/applyconfiguration --output-package ${OUTPUT_PKG}/clientset --go-header-file ${HEADER}
+
+${GOBIN}/lister-gen --input-dirs ${APIS} --output-package ${OUTPUT_PKG}/listers --go-header-file ${HEADER}
+
+${GOBIN}/informer-gen --input-dirs ${APIS} --versioned-clientset-package ${OUTPUT_PKG}/clientset/versioned --listers-package ${OUTPUT_PKG}/listers --output-package ${OUTPUT_PKG}/informers --go-header-file ${HEADER}
@@ -18,7 +18,10 @@ import "errors"
 
 type Config struct {
 	ListenAddress string `mapstructure:"listenAddress"`
-	Endpoint      string `mapstructure:"endpoint"`
+	// Endpoint is the liveness endpoint
+	Endpoint string `mapstructure:"endpoint"`
+	// ReadinessEndpoint is the readiness endpoint
+	ReadinessEndpoint string `mapstructure:"readinessEndpoint"`
 }
 
 // Validate checks that the configuration is valid.
@@ -31,5 +34,13 @@ func (c Config) Validate() error {
 		return errors.New("endpoint must not be empty")
 	}
 
+	if c.ReadinessEndpoint == "" {
+		return errors.New("readiness endpoint must not be empty")
+	}
+
+	if c.ReadinessEndpoint == c.Endpoint {
+		return errors.New("readiness endpoint must differ from the liveness endpoint")
+	}
+
 	return nil
 }
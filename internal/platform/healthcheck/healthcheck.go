@@ -15,7 +15,9 @@
 package healthcheck
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"emperror.dev/emperror"
 	"github.com/gin-gonic/gin"
@@ -23,16 +25,68 @@ import (
 	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
 )
 
-// New runs the health check endpoint
-func New(config Config, logger log.Logger, errorHandler emperror.Handler) {
-	logger.WithFields(log.Fields{"addr": config.ListenAddress, "endpoint": config.Endpoint}).Info("starting health check http server")
+const (
+	shutdownTimeout = 5 * time.Second
+)
+
+// Checker reports the health of a component
+type Checker interface {
+	// Live returns an error if the component is stuck and should be restarted
+	Live() error
+	// Ready returns an error if the component is not able to do its job
+	Ready() error
+}
+
+// Handler registers additional endpoints on the health check server
+type Handler interface {
+	RegisterRoutes(r gin.IRouter)
+}
+
+// New runs the liveness and readiness endpoints until the context is cancelled
+func New(ctx context.Context, config Config, checker Checker, logger log.Logger, errorHandler emperror.Handler, handlers ...Handler) {
+	logger.WithFields(log.Fields{
+		"addr":              config.ListenAddress,
+		"endpoint":          config.Endpoint,
+		"readinessEndpoint": config.ReadinessEndpoint,
+	}).Info("starting health check http server")
 
 	r := gin.New()
-	r.GET(config.Endpoint, func(c *gin.Context) {
-		c.String(http.StatusOK, "ok")
-	})
-	err := r.Run(config.ListenAddress)
-	if err != nil {
+	r.GET(config.Endpoint, handler(checker.Live, logger))
+	r.GET(config.ReadinessEndpoint, handler(checker.Ready, logger))
+	for _, h := range handlers {
+		h.RegisterRoutes(r)
+	}
+
+	server := &http.Server{
+		Addr:    config.ListenAddress,
+		Handler: r,
+	}
+
+	go func() {
+		<-ctx.Done()
+		logger.Info("shutting down health check http server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			errorHandler.Handle(err)
+		}
+	}()
+
+	err := server.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
 		errorHandler.Handle(err)
 	}
 }
+
+func handler(check func() error, logger log.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := check(); err != nil {
+			logger.WithField("path", c.Request.URL.Path).Warn(err.Error())
+			c.String(http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		c.String(http.StatusOK, "ok")
+	}
+}
@@ -16,6 +16,7 @@ package log
 
 import (
 	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // Config holds details necessary for logging.
@@ -29,6 +30,14 @@ type Config struct {
 
 	// NoColor makes sure that no log output gets colorized.
 	NoColor bool `mapstructure:"noColor"`
+
+	// Components holds the minimum log level of named component loggers,
+	// components which are not listed use Level.
+	Components map[string]string `mapstructure:"components"`
+
+	// LevelEndpoint is the health check server endpoint where the log levels can
+	// be queried and changed at runtime, it is disabled if empty.
+	LevelEndpoint string `mapstructure:"levelEndpoint"`
 }
 
 // Validate validates the configuration.
@@ -41,5 +50,11 @@ func (c Config) Validate() error {
 		return errors.New("invalid log format: " + c.Format)
 	}
 
+	for component, level := range c.Components {
+		if _, err := logrus.ParseLevel(level); err != nil {
+			return errors.New("invalid log level for component " + component + ": " + level)
+		}
+	}
+
 	return nil
 }
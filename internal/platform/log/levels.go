@@ -0,0 +1,206 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"net/http"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// RootComponent is the name of the root logger in the level listings
+	RootComponent = "root"
+)
+
+// Levels changes the level of the root logger and the component loggers at runtime
+type Levels struct {
+	config Config
+
+	mu         sync.Mutex
+	root       *logrus.Logger
+	rootLevel  logrus.Level
+	components map[string]*logrus.Logger
+	overrides  map[string]logrus.Level
+}
+
+func newLevels(config Config) *Levels {
+	root := newLogrusLogger(config)
+
+	overrides := make(map[string]logrus.Level, len(config.Components))
+	for component, level := range config.Components {
+		if l, err := logrus.ParseLevel(level); err == nil {
+			overrides[component] = l
+		}
+	}
+
+	return &Levels{
+		config: config,
+
+		root:       root,
+		rootLevel:  root.GetLevel(),
+		components: make(map[string]*logrus.Logger),
+		overrides:  overrides,
+	}
+}
+
+func (l *Levels) component(name string) *logrus.Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if logger, ok := l.components[name]; ok {
+		return logger
+	}
+
+	logger := newLogrusLogger(l.config)
+	logger.SetLevel(l.root.GetLevel())
+	if level, ok := l.overrides[name]; ok {
+		logger.SetLevel(level)
+	}
+	l.components[name] = logger
+
+	return logger
+}
+
+// Get gives back the current level of the root logger and each component logger
+func (l *Levels) Get() map[string]string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	levels := map[string]string{
+		RootComponent: l.root.GetLevel().String(),
+	}
+	for name, logger := range l.components {
+		levels[name] = logger.GetLevel().String()
+	}
+	for name, level := range l.overrides {
+		if _, ok := levels[name]; !ok {
+			levels[name] = level.String()
+		}
+	}
+
+	return levels
+}
+
+// Set changes the level of a component logger, or the level of the root logger
+// and every component logger without its own level if the component is empty
+// or RootComponent
+func (l *Levels) Set(component string, level string) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "invalid log level", "level", level)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if component == "" || component == RootComponent {
+		l.setRootLevel(lvl)
+		return nil
+	}
+
+	l.overrides[component] = lvl
+	if logger, ok := l.components[component]; ok {
+		logger.SetLevel(lvl)
+	}
+
+	return nil
+}
+
+// ToggleDebug switches the root logger between the debug level and the
+// configured level, and gives back the new level
+func (l *Levels) ToggleDebug() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	level := logrus.DebugLevel
+	if l.root.IsLevelEnabled(logrus.DebugLevel) {
+		level = l.rootLevel
+		if level >= logrus.DebugLevel {
+			level = logrus.InfoLevel
+		}
+	}
+	l.setRootLevel(level)
+
+	return level.String()
+}
+
+// UpdateConfig applies the root and component levels of a changed
+// configuration, the levels changed at runtime are overridden
+func (l *Levels) UpdateConfig(config Config) {
+	rootLevel := logrus.InfoLevel
+	if level, err := logrus.ParseLevel(config.Level); err == nil {
+		rootLevel = level
+	}
+
+	overrides := make(map[string]logrus.Level, len(config.Components))
+	for component, level := range config.Components {
+		if l, err := logrus.ParseLevel(level); err == nil {
+			overrides[component] = l
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.config.Level = config.Level
+	l.config.Components = config.Components
+	l.rootLevel = rootLevel
+	l.overrides = overrides
+
+	l.setRootLevel(rootLevel)
+	for name, logger := range l.components {
+		if level, ok := overrides[name]; ok {
+			logger.SetLevel(level)
+		}
+	}
+}
+
+func (l *Levels) setRootLevel(level logrus.Level) {
+	l.root.SetLevel(level)
+	for name, logger := range l.components {
+		if _, ok := l.overrides[name]; !ok {
+			logger.SetLevel(level)
+		}
+	}
+}
+
+// RegisterRoutes registers the endpoint which lists (GET) and changes (PUT) the log levels
+func (l *Levels) RegisterRoutes(r gin.IRouter) {
+	r.GET(l.config.LevelEndpoint, func(c *gin.Context) {
+		c.JSON(http.StatusOK, l.Get())
+	})
+
+	r.PUT(l.config.LevelEndpoint, func(c *gin.Context) {
+		var request struct {
+			Component string `json:"component"`
+			Level     string `json:"level"`
+		}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := l.Set(request.Component, request.Level); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, l.Get())
+	})
+}
@@ -33,6 +33,7 @@ type Logger interface {
 	Errorf(format string, args ...interface{})
 	WithFields(fields Fields) Logger
 	WithField(key string, value interface{}) Logger
+	Component(name string) Logger
 }
 
 // Fields is an alias to log.Fields for easier usage.
@@ -42,3 +43,9 @@ type Fields = map[string]interface{}
 func NewLogger(config Config) Logger {
 	return NewLogrusLogger(config)
 }
+
+// NewLoggerWithLevels creates a new logger along with the controller of its
+// levels which can be used to change them at runtime.
+func NewLoggerWithLevels(config Config) (Logger, *Levels) {
+	return NewLogrusLoggerWithLevels(config)
+}
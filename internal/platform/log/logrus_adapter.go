@@ -22,20 +22,49 @@ import (
 
 type logrusAdapter struct {
 	*logrus.Entry
+
+	levels *Levels
 }
 
 // WithField adds a single field to the Entry
 func (a *logrusAdapter) WithField(key string, value interface{}) Logger {
-	return &logrusAdapter{a.Entry.WithField(key, value)}
+	return &logrusAdapter{a.Entry.WithField(key, value), a.levels}
 }
 
 // WithFields returns a new logger based on the original logger with
 // the additional supplied fields.
 func (a *logrusAdapter) WithFields(fields Fields) Logger {
-	return &logrusAdapter{a.Entry.WithFields(logrus.Fields(fields))}
+	return &logrusAdapter{a.Entry.WithFields(logrus.Fields(fields)), a.levels}
+}
+
+// Component returns a named sub-logger with the fields of the original logger
+// whose level can be configured independently
+func (a *logrusAdapter) Component(name string) Logger {
+	entry := logrus.NewEntry(a.levels.component(name)).
+		WithFields(a.Entry.Data).
+		WithField("component", name)
+
+	return &logrusAdapter{entry, a.levels}
 }
 
 func NewLogrusLogger(config Config) Logger {
+	logger, _ := NewLogrusLoggerWithLevels(config)
+
+	return logger
+}
+
+// NewLogrusLoggerWithLevels creates a new logger along with the controller of
+// its levels and the levels of its component loggers
+func NewLogrusLoggerWithLevels(config Config) (Logger, *Levels) {
+	levels := newLevels(config)
+
+	return &logrusAdapter{
+		logrus.NewEntry(levels.root),
+		levels,
+	}, levels
+}
+
+func newLogrusLogger(config Config) *logrus.Logger {
 	logger := logrus.New()
 
 	logger.SetOutput(os.Stdout)
@@ -56,7 +85,5 @@ func NewLogrusLogger(config Config) Logger {
 		logger.SetLevel(level)
 	}
 
-	return &logrusAdapter{
-		logrus.NewEntry(logger),
-	}
+	return logger
 }
@@ -16,10 +16,10 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
-// +genclient:noStatus
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // NodePoolLabelSet is a specification for a NodePoolLabelSet resource
@@ -34,12 +34,48 @@ type NodePoolLabelSet struct {
 // NodePoolLabelSetSpec is the spec for an NodePoolLabelSet resource
 type NodePoolLabelSetSpec struct {
 	Labels map[string]string `json:"labels"`
+	// Rollout configures a staged rollout of label changes, every node
+	// of the pool is updated at once if it is not set
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+	// Paused stops the reconciliation of the nodes of the pool, drift is still reported
+	Paused bool `json:"paused,omitempty"`
+}
+
+// RolloutStrategy describes how label changes are applied to the nodes of a pool
+type RolloutStrategy struct {
+	// MaxUnavailable is the number or percentage of nodes which may be unavailable
+	// while a batch is updated, the unavailable nodes count against it, defaults to 1
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// PauseBetweenBatches is the time to wait before updating the next batch
+	PauseBetweenBatches *metav1.Duration `json:"pauseBetweenBatches,omitempty"`
+	// Paused stops the rollout before the next batch
+	Paused bool `json:"paused,omitempty"`
 }
 
 // NodePoolLabelSetStatus is the status for an NodePoolLabelSet resource
 type NodePoolLabelSetStatus struct {
-	State   NodePoolLabelSetState `json:"state,omitempty"`
-	Message string                `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the NodePoolLabelSet last reconciled,
+	// it is only set through the v1beta1 API
+	ObservedGeneration int64                 `json:"observedGeneration,omitempty"`
+	State              NodePoolLabelSetState `json:"state,omitempty"`
+	Message            string                `json:"message,omitempty"`
+	// Rollout holds the progress of the staged rollout
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+	// Conditions are the latest observations of the state of the NodePoolLabelSet,
+	// they are only set through the v1beta1 API
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// RolloutStatus is the progress of a staged rollout
+type RolloutStatus struct {
+	// ObservedGeneration is the generation of the NodePoolLabelSet being rolled out
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// UpdatedNodes is the number of nodes which already have the desired labels
+	UpdatedNodes int32 `json:"updatedNodes"`
+	// TotalNodes is the number of nodes of the pool
+	TotalNodes int32 `json:"totalNodes"`
+	// LastBatchTime is the time when the last batch was updated
+	LastBatchTime *metav1.Time `json:"lastBatchTime,omitempty"`
 }
 
 type NodePoolLabelSetState string
@@ -48,6 +84,7 @@ const (
 	NodePoolLabelSetStateCreated NodePoolLabelSetState = "Created"
 	NodePoolLabelSetStateSyncing NodePoolLabelSetState = "Syncing"
 	NodePoolLabelSetStateSynced  NodePoolLabelSetState = "Synced"
+	NodePoolLabelSetStatePaused  NodePoolLabelSetState = "Paused"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -0,0 +1,115 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"encoding/json"
+
+	"emperror.dev/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+)
+
+// PreservedFieldsAnnotation held the v1beta1 status fields of an object
+// stored as v1alpha1 before they were added to the v1alpha1 status
+const PreservedFieldsAnnotation = "labels.banzaicloud.io/v1beta1-fields"
+
+// preservedFields are the fields read from the PreservedFieldsAnnotation
+type preservedFields struct {
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// ConvertFromV1alpha1 converts a v1alpha1 NodePoolLabelSet to v1beta1, the
+// status fields of the PreservedFieldsAnnotation are restored if the status
+// doesn't have them
+func ConvertFromV1alpha1(in *v1alpha1.NodePoolLabelSet, out *NodePoolLabelSet) error {
+	in = in.DeepCopy()
+
+	var fields preservedFields
+	if raw, ok := in.Annotations[PreservedFieldsAnnotation]; ok {
+		if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+			return errors.WrapIfWithDetails(err, "could not unmarshal preserved fields", "name", in.Name)
+		}
+		delete(in.Annotations, PreservedFieldsAnnotation)
+		if len(in.Annotations) == 0 {
+			in.Annotations = nil
+		}
+	}
+
+	out.TypeMeta = metav1.TypeMeta{
+		APIVersion: SchemeGroupVersion.String(),
+		Kind:       "NodePoolLabelSet",
+	}
+	out.ObjectMeta = in.ObjectMeta
+	out.Spec = NodePoolLabelSetSpec{
+		Labels:  in.Spec.Labels,
+		Rollout: (*RolloutStrategy)(in.Spec.Rollout),
+		Paused:  in.Spec.Paused,
+	}
+	out.Status = NodePoolLabelSetStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		State:              NodePoolLabelSetState(in.Status.State),
+		Message:            in.Status.Message,
+		Rollout:            (*RolloutStatus)(in.Status.Rollout),
+		Conditions:         in.Status.Conditions,
+	}
+	if out.Status.ObservedGeneration == 0 {
+		out.Status.ObservedGeneration = fields.ObservedGeneration
+	}
+	if len(out.Status.Conditions) == 0 {
+		out.Status.Conditions = fields.Conditions
+	}
+
+	return nil
+}
+
+// ConvertToV1alpha1 converts a v1beta1 NodePoolLabelSet to v1alpha1
+func ConvertToV1alpha1(in *NodePoolLabelSet, out *v1alpha1.NodePoolLabelSet) error {
+	in = in.DeepCopy()
+
+	// the status fields of the annotation are stored in the status from now on
+	delete(in.Annotations, PreservedFieldsAnnotation)
+	if len(in.Annotations) == 0 {
+		in.Annotations = nil
+	}
+
+	// labels are required in v1alpha1
+	labels := in.Spec.Labels
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+
+	out.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       "NodePoolLabelSet",
+	}
+	out.ObjectMeta = in.ObjectMeta
+	out.Spec = v1alpha1.NodePoolLabelSetSpec{
+		Labels:  labels,
+		Rollout: (*v1alpha1.RolloutStrategy)(in.Spec.Rollout),
+		Paused:  in.Spec.Paused,
+	}
+	out.Status = v1alpha1.NodePoolLabelSetStatus{
+		ObservedGeneration: in.Status.ObservedGeneration,
+		State:              v1alpha1.NodePoolLabelSetState(in.Status.State),
+		Message:            in.Status.Message,
+		Rollout:            (*v1alpha1.RolloutStatus)(in.Status.Rollout),
+		Conditions:         in.Status.Conditions,
+	}
+
+	return nil
+}
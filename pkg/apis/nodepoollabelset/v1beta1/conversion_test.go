@@ -0,0 +1,208 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+)
+
+var (
+	testTime           = metav1.NewTime(time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC))
+	testMaxUnavailable = intstr.FromString("25%")
+)
+
+func testV1beta1Set() *NodePoolLabelSet {
+	return &NodePoolLabelSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "NodePoolLabelSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "pool",
+			Namespace:   "default",
+			Generation:  3,
+			Annotations: map[string]string{"example.com/note": "kept"},
+		},
+		Spec: NodePoolLabelSetSpec{
+			Labels: map[string]string{"env": "prod"},
+			Rollout: &RolloutStrategy{
+				MaxUnavailable:      &testMaxUnavailable,
+				PauseBetweenBatches: &metav1.Duration{Duration: time.Minute},
+			},
+			Paused: true,
+		},
+		Status: NodePoolLabelSetStatus{
+			ObservedGeneration: 2,
+			State:              NodePoolLabelSetStateSyncing,
+			Message:            "1 nodes are outdated",
+			Rollout: &RolloutStatus{
+				ObservedGeneration: 2,
+				UpdatedNodes:       1,
+				TotalNodes:         2,
+				LastBatchTime:      &testTime,
+			},
+			Conditions: []metav1.Condition{{
+				Type:               "Synced",
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: 2,
+				LastTransitionTime: testTime,
+				Reason:             "RolloutInProgress",
+				Message:            "1 nodes are outdated",
+			}},
+		},
+	}
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *NodePoolLabelSet
+	}{
+		{
+			name: "every field set",
+			in:   testV1beta1Set(),
+		},
+		{
+			name: "only v1alpha1 fields set",
+			in: &NodePoolLabelSet{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: SchemeGroupVersion.String(),
+					Kind:       "NodePoolLabelSet",
+				},
+				ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"},
+				Spec: NodePoolLabelSetSpec{
+					Labels: map[string]string{"env": "prod"},
+				},
+				Status: NodePoolLabelSetStatus{
+					State: NodePoolLabelSetStateSynced,
+				},
+			},
+		},
+		{
+			name: "only status fields set",
+			in: &NodePoolLabelSet{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: SchemeGroupVersion.String(),
+					Kind:       "NodePoolLabelSet",
+				},
+				ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: "default"},
+				Status:     testV1beta1Set().Status,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var stored v1alpha1.NodePoolLabelSet
+			if err := ConvertToV1alpha1(test.in, &stored); err != nil {
+				t.Fatal(err)
+			}
+
+			if stored.Status.ObservedGeneration != test.in.Status.ObservedGeneration ||
+				!equality.Semantic.DeepEqual(stored.Status.Conditions, test.in.Status.Conditions) {
+				t.Errorf("expected the status fields in the v1alpha1 status, got %+v", stored.Status)
+			}
+
+			var out NodePoolLabelSet
+			if err := ConvertFromV1alpha1(&stored, &out); err != nil {
+				t.Fatal(err)
+			}
+
+			if !equality.Semantic.DeepEqual(test.in, &out) {
+				t.Fatalf("round trip changed the object\nexpected: %+v\ngot:      %+v", test.in, &out)
+			}
+
+			var storedAgain v1alpha1.NodePoolLabelSet
+			if err := ConvertToV1alpha1(&out, &storedAgain); err != nil {
+				t.Fatal(err)
+			}
+			if !equality.Semantic.DeepEqual(&stored, &storedAgain) {
+				t.Fatalf("round trip changed the stored object\nexpected: %+v\ngot:      %+v", &stored, &storedAgain)
+			}
+		})
+	}
+}
+
+func TestConversionOfStatusWrite(t *testing.T) {
+	var stored v1alpha1.NodePoolLabelSet
+	if err := ConvertToV1alpha1(testV1beta1Set(), &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	// a write through the status subresource only changes the status of the stored object
+	update := testV1beta1Set()
+	update.Status.ObservedGeneration = 3
+	update.Status.Conditions[0].Status = metav1.ConditionTrue
+	var converted v1alpha1.NodePoolLabelSet
+	if err := ConvertToV1alpha1(update, &converted); err != nil {
+		t.Fatal(err)
+	}
+	stored.Status = converted.Status
+
+	var out NodePoolLabelSet
+	if err := ConvertFromV1alpha1(&stored, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !equality.Semantic.DeepEqual(update.Status, out.Status) {
+		t.Fatalf("status write was lost\nexpected: %+v\ngot:      %+v", update.Status, out.Status)
+	}
+}
+
+func TestConversionOfLegacyPreservedStatus(t *testing.T) {
+	stored := &v1alpha1.NodePoolLabelSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pool",
+			Namespace: "default",
+			Annotations: map[string]string{
+				PreservedFieldsAnnotation: `{"observedGeneration":2,"conditions":[{"type":"Synced","status":"True","lastTransitionTime":"2019-10-01T12:00:00Z","reason":"Synced","message":""}]}`,
+			},
+		},
+	}
+
+	var out NodePoolLabelSet
+	if err := ConvertFromV1alpha1(stored, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Status.ObservedGeneration != 2 || len(out.Status.Conditions) != 1 {
+		t.Fatalf("expected the status fields of the annotation, got %+v", out.Status)
+	}
+	if _, ok := out.Annotations[PreservedFieldsAnnotation]; ok {
+		t.Fatal("expected the preserved fields annotation to be removed")
+	}
+}
+
+func TestConversionDefaultsLabels(t *testing.T) {
+	in := testV1beta1Set()
+	in.Spec.Labels = nil
+
+	var stored v1alpha1.NodePoolLabelSet
+	if err := ConvertToV1alpha1(in, &stored); err != nil {
+		t.Fatal(err)
+	}
+
+	if stored.Spec.Labels == nil || len(stored.Spec.Labels) != 0 {
+		t.Fatalf("expected empty labels, got %#v", stored.Spec.Labels)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodePoolLabelSet is a specification for a NodePoolLabelSet resource
+type NodePoolLabelSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   NodePoolLabelSetSpec   `json:"spec"`
+	Status NodePoolLabelSetStatus `json:"status,omitempty"`
+}
+
+// NodePoolLabelSetSpec is the spec for an NodePoolLabelSet resource
+type NodePoolLabelSetSpec struct {
+	// Labels are the labels set on the nodes of the pool
+	Labels map[string]string `json:"labels,omitempty"`
+	// Rollout configures a staged rollout of label changes, every node
+	// of the pool is updated at once if it is not set
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
+	// Paused stops the reconciliation of the nodes of the pool, drift is still reported
+	Paused bool `json:"paused,omitempty"`
+}
+
+// RolloutStrategy describes how label changes are applied to the nodes of a pool
+type RolloutStrategy struct {
+	// MaxUnavailable is the number or percentage of nodes which may be unavailable
+	// while a batch is updated, the unavailable nodes count against it, defaults to 1
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// PauseBetweenBatches is the time to wait before updating the next batch
+	PauseBetweenBatches *metav1.Duration `json:"pauseBetweenBatches,omitempty"`
+	// Paused stops the rollout before the next batch
+	Paused bool `json:"paused,omitempty"`
+}
+
+// NodePoolLabelSetStatus is the status for an NodePoolLabelSet resource
+type NodePoolLabelSetStatus struct {
+	// ObservedGeneration is the generation of the NodePoolLabelSet last reconciled
+	ObservedGeneration int64                 `json:"observedGeneration,omitempty"`
+	State              NodePoolLabelSetState `json:"state,omitempty"`
+	Message            string                `json:"message,omitempty"`
+	// Rollout holds the progress of the staged rollout
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+	// Conditions are the latest observations of the state of the NodePoolLabelSet
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// RolloutStatus is the progress of a staged rollout
+type RolloutStatus struct {
+	// ObservedGeneration is the generation of the NodePoolLabelSet being rolled out
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// UpdatedNodes is the number of nodes which already have the desired labels
+	UpdatedNodes int32 `json:"updatedNodes"`
+	// TotalNodes is the number of nodes of the pool
+	TotalNodes int32 `json:"totalNodes"`
+	// LastBatchTime is the time when the last batch was updated
+	LastBatchTime *metav1.Time `json:"lastBatchTime,omitempty"`
+}
+
+type NodePoolLabelSetState string
+
+const (
+	NodePoolLabelSetStateCreated NodePoolLabelSetState = "Created"
+	NodePoolLabelSetStateSyncing NodePoolLabelSetState = "Syncing"
+	NodePoolLabelSetStateSynced  NodePoolLabelSetState = "Synced"
+	NodePoolLabelSetStatePaused  NodePoolLabelSetState = "Paused"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodePoolLabelSetList is a list of NodePoolLabelSet resources
+type NodePoolLabelSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []NodePoolLabelSet `json:"items"`
+}
@@ -0,0 +1,83 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	v1 "k8s.io/client-go/applyconfigurations/meta/v1"
+)
+
+// NodePoolLabelSetStatusApplyConfiguration represents an declarative configuration of the NodePoolLabelSetStatus type for use
+// with apply.
+type NodePoolLabelSetStatusApplyConfiguration struct {
+	ObservedGeneration *int64                           `json:"observedGeneration,omitempty"`
+	State              *v1alpha1.NodePoolLabelSetState  `json:"state,omitempty"`
+	Message            *string                          `json:"message,omitempty"`
+	Rollout            *RolloutStatusApplyConfiguration `json:"rollout,omitempty"`
+	Conditions         []v1.ConditionApplyConfiguration `json:"conditions,omitempty"`
+}
+
+// NodePoolLabelSetStatusApplyConfiguration constructs an declarative configuration of the NodePoolLabelSetStatus type for use with
+// apply.
+func NodePoolLabelSetStatus() *NodePoolLabelSetStatusApplyConfiguration {
+	return &NodePoolLabelSetStatusApplyConfiguration{}
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *NodePoolLabelSetStatusApplyConfiguration) WithObservedGeneration(value int64) *NodePoolLabelSetStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
+
+// WithState sets the State field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the State field is set to the value of the last call.
+func (b *NodePoolLabelSetStatusApplyConfiguration) WithState(value v1alpha1.NodePoolLabelSetState) *NodePoolLabelSetStatusApplyConfiguration {
+	b.State = &value
+	return b
+}
+
+// WithMessage sets the Message field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Message field is set to the value of the last call.
+func (b *NodePoolLabelSetStatusApplyConfiguration) WithMessage(value string) *NodePoolLabelSetStatusApplyConfiguration {
+	b.Message = &value
+	return b
+}
+
+// WithRollout sets the Rollout field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Rollout field is set to the value of the last call.
+func (b *NodePoolLabelSetStatusApplyConfiguration) WithRollout(value *RolloutStatusApplyConfiguration) *NodePoolLabelSetStatusApplyConfiguration {
+	b.Rollout = value
+	return b
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *NodePoolLabelSetStatusApplyConfiguration) WithConditions(values ...*v1.ConditionApplyConfiguration) *NodePoolLabelSetStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConditions")
+		}
+		b.Conditions = append(b.Conditions, *values[i])
+	}
+	return b
+}
@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// NodePoolLabelSetSpecApplyConfiguration represents an declarative configuration of the NodePoolLabelSetSpec type for use
+// with apply.
+type NodePoolLabelSetSpecApplyConfiguration struct {
+	Labels  map[string]string                  `json:"labels,omitempty"`
+	Rollout *RolloutStrategyApplyConfiguration `json:"rollout,omitempty"`
+	Paused  *bool                              `json:"paused,omitempty"`
+}
+
+// NodePoolLabelSetSpecApplyConfiguration constructs an declarative configuration of the NodePoolLabelSetSpec type for use with
+// apply.
+func NodePoolLabelSetSpec() *NodePoolLabelSetSpecApplyConfiguration {
+	return &NodePoolLabelSetSpecApplyConfiguration{}
+}
+
+// WithLabels puts the entries into the Labels field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the Labels field,
+// overwriting an existing map entries in Labels field with the same key.
+func (b *NodePoolLabelSetSpecApplyConfiguration) WithLabels(entries map[string]string) *NodePoolLabelSetSpecApplyConfiguration {
+	if b.Labels == nil && len(entries) > 0 {
+		b.Labels = make(map[string]string, len(entries))
+	}
+	for k, v := range entries {
+		b.Labels[k] = v
+	}
+	return b
+}
+
+// WithRollout sets the Rollout field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Rollout field is set to the value of the last call.
+func (b *NodePoolLabelSetSpecApplyConfiguration) WithRollout(value *RolloutStrategyApplyConfiguration) *NodePoolLabelSetSpecApplyConfiguration {
+	b.Rollout = value
+	return b
+}
+
+// WithPaused sets the Paused field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Paused field is set to the value of the last call.
+func (b *NodePoolLabelSetSpecApplyConfiguration) WithPaused(value bool) *NodePoolLabelSetSpecApplyConfiguration {
+	b.Paused = &value
+	return b
+}
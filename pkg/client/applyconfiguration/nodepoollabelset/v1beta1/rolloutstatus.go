@@ -0,0 +1,68 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RolloutStatusApplyConfiguration represents an declarative configuration of the RolloutStatus type for use
+// with apply.
+type RolloutStatusApplyConfiguration struct {
+	ObservedGeneration *int64   `json:"observedGeneration,omitempty"`
+	UpdatedNodes       *int32   `json:"updatedNodes,omitempty"`
+	TotalNodes         *int32   `json:"totalNodes,omitempty"`
+	LastBatchTime      *v1.Time `json:"lastBatchTime,omitempty"`
+}
+
+// RolloutStatusApplyConfiguration constructs an declarative configuration of the RolloutStatus type for use with
+// apply.
+func RolloutStatus() *RolloutStatusApplyConfiguration {
+	return &RolloutStatusApplyConfiguration{}
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *RolloutStatusApplyConfiguration) WithObservedGeneration(value int64) *RolloutStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
+
+// WithUpdatedNodes sets the UpdatedNodes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UpdatedNodes field is set to the value of the last call.
+func (b *RolloutStatusApplyConfiguration) WithUpdatedNodes(value int32) *RolloutStatusApplyConfiguration {
+	b.UpdatedNodes = &value
+	return b
+}
+
+// WithTotalNodes sets the TotalNodes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TotalNodes field is set to the value of the last call.
+func (b *RolloutStatusApplyConfiguration) WithTotalNodes(value int32) *RolloutStatusApplyConfiguration {
+	b.TotalNodes = &value
+	return b
+}
+
+// WithLastBatchTime sets the LastBatchTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastBatchTime field is set to the value of the last call.
+func (b *RolloutStatusApplyConfiguration) WithLastBatchTime(value v1.Time) *RolloutStatusApplyConfiguration {
+	b.LastBatchTime = &value
+	return b
+}
@@ -0,0 +1,60 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// RolloutStrategyApplyConfiguration represents an declarative configuration of the RolloutStrategy type for use
+// with apply.
+type RolloutStrategyApplyConfiguration struct {
+	MaxUnavailable      *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	PauseBetweenBatches *v1.Duration        `json:"pauseBetweenBatches,omitempty"`
+	Paused              *bool               `json:"paused,omitempty"`
+}
+
+// RolloutStrategyApplyConfiguration constructs an declarative configuration of the RolloutStrategy type for use with
+// apply.
+func RolloutStrategy() *RolloutStrategyApplyConfiguration {
+	return &RolloutStrategyApplyConfiguration{}
+}
+
+// WithMaxUnavailable sets the MaxUnavailable field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxUnavailable field is set to the value of the last call.
+func (b *RolloutStrategyApplyConfiguration) WithMaxUnavailable(value intstr.IntOrString) *RolloutStrategyApplyConfiguration {
+	b.MaxUnavailable = &value
+	return b
+}
+
+// WithPauseBetweenBatches sets the PauseBetweenBatches field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PauseBetweenBatches field is set to the value of the last call.
+func (b *RolloutStrategyApplyConfiguration) WithPauseBetweenBatches(value v1.Duration) *RolloutStrategyApplyConfiguration {
+	b.PauseBetweenBatches = &value
+	return b
+}
+
+// WithPaused sets the Paused field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Paused field is set to the value of the last call.
+func (b *RolloutStrategyApplyConfiguration) WithPaused(value bool) *RolloutStrategyApplyConfiguration {
+	b.Paused = &value
+	return b
+}
@@ -0,0 +1,57 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package applyconfiguration
+
+import (
+	v1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	v1beta1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1beta1"
+	labelsv1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/applyconfiguration/nodepoollabelset/v1alpha1"
+	labelsv1beta1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/applyconfiguration/nodepoollabelset/v1beta1"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ForKind returns an apply configuration type for the given GroupVersionKind, or nil if no
+// apply configuration type exists for the given GroupVersionKind.
+func ForKind(kind schema.GroupVersionKind) interface{} {
+	switch kind {
+	// Group=labels.banzaicloud.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("NodePoolLabelSet"):
+		return &labelsv1alpha1.NodePoolLabelSetApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("NodePoolLabelSetSpec"):
+		return &labelsv1alpha1.NodePoolLabelSetSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("NodePoolLabelSetStatus"):
+		return &labelsv1alpha1.NodePoolLabelSetStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RolloutStatus"):
+		return &labelsv1alpha1.RolloutStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("RolloutStrategy"):
+		return &labelsv1alpha1.RolloutStrategyApplyConfiguration{}
+
+		// Group=labels.banzaicloud.io, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithKind("NodePoolLabelSet"):
+		return &labelsv1beta1.NodePoolLabelSetApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("NodePoolLabelSetSpec"):
+		return &labelsv1beta1.NodePoolLabelSetSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("NodePoolLabelSetStatus"):
+		return &labelsv1beta1.NodePoolLabelSetStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RolloutStatus"):
+		return &labelsv1beta1.RolloutStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RolloutStrategy"):
+		return &labelsv1beta1.RolloutStrategyApplyConfiguration{}
+
+	}
+	return nil
+}
@@ -17,7 +17,10 @@
 package versioned
 
 import (
+	"fmt"
+
 	labelsv1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/typed/nodepoollabelset/v1alpha1"
+	labelsv1beta1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/typed/nodepoollabelset/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -26,8 +29,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	LabelsV1alpha1() labelsv1alpha1.LabelsV1alpha1Interface
-	// Deprecated: please explicitly pick a version if possible.
-	Labels() labelsv1alpha1.LabelsV1alpha1Interface
+	LabelsV1beta1() labelsv1beta1.LabelsV1beta1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -35,6 +37,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	labelsV1alpha1 *labelsv1alpha1.LabelsV1alpha1Client
+	labelsV1beta1  *labelsv1beta1.LabelsV1beta1Client
 }
 
 // LabelsV1alpha1 retrieves the LabelsV1alpha1Client
@@ -42,10 +45,9 @@ func (c *Clientset) LabelsV1alpha1() labelsv1alpha1.LabelsV1alpha1Interface {
 	return c.labelsV1alpha1
 }
 
-// Deprecated: Labels retrieves the default version of LabelsClient.
-// Please explicitly pick a version.
-func (c *Clientset) Labels() labelsv1alpha1.LabelsV1alpha1Interface {
-	return c.labelsV1alpha1
+// LabelsV1beta1 retrieves the LabelsV1beta1Client
+func (c *Clientset) LabelsV1beta1() labelsv1beta1.LabelsV1beta1Interface {
+	return c.labelsV1beta1
 }
 
 // Discovery retrieves the DiscoveryClient
@@ -57,9 +59,14 @@ func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 }
 
 // NewForConfig creates a new Clientset for the given config.
+// If config's RateLimiter is not set and QPS and Burst are acceptable,
+// NewForConfig will generate a rate-limiter in configShallowCopy.
 func NewForConfig(c *rest.Config) (*Clientset, error) {
 	configShallowCopy := *c
 	if configShallowCopy.RateLimiter == nil && configShallowCopy.QPS > 0 {
+		if configShallowCopy.Burst <= 0 {
+			return nil, fmt.Errorf("burst is required to be greater than 0 when RateLimiter is not set and QPS is set to greater than 0")
+		}
 		configShallowCopy.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(configShallowCopy.QPS, configShallowCopy.Burst)
 	}
 	var cs Clientset
@@ -68,6 +75,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.labelsV1beta1, err = labelsv1beta1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -81,6 +92,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.labelsV1alpha1 = labelsv1alpha1.NewForConfigOrDie(c)
+	cs.labelsV1beta1 = labelsv1beta1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -90,6 +102,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.labelsV1alpha1 = labelsv1alpha1.New(c)
+	cs.labelsV1beta1 = labelsv1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
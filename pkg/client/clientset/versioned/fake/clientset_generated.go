@@ -20,6 +20,8 @@ import (
 	clientset "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned"
 	labelsv1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/typed/nodepoollabelset/v1alpha1"
 	fakelabelsv1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/typed/nodepoollabelset/v1alpha1/fake"
+	labelsv1beta1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/typed/nodepoollabelset/v1beta1"
+	fakelabelsv1beta1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/typed/nodepoollabelset/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -39,7 +41,7 @@ func NewSimpleClientset(objects ...runtime.Object) *Clientset {
 		}
 	}
 
-	cs := &Clientset{}
+	cs := &Clientset{tracker: o}
 	cs.discovery = &fakediscovery.FakeDiscovery{Fake: &cs.Fake}
 	cs.AddReactor("*", "*", testing.ObjectReaction(o))
 	cs.AddWatchReactor("*", func(action testing.Action) (handled bool, ret watch.Interface, err error) {
@@ -61,12 +63,17 @@ func NewSimpleClientset(objects ...runtime.Object) *Clientset {
 type Clientset struct {
 	testing.Fake
 	discovery *fakediscovery.FakeDiscovery
+	tracker   testing.ObjectTracker
 }
 
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	return c.discovery
 }
 
+func (c *Clientset) Tracker() testing.ObjectTracker {
+	return c.tracker
+}
+
 var _ clientset.Interface = &Clientset{}
 
 // LabelsV1alpha1 retrieves the LabelsV1alpha1Client
@@ -74,7 +81,7 @@ func (c *Clientset) LabelsV1alpha1() labelsv1alpha1.LabelsV1alpha1Interface {
 	return &fakelabelsv1alpha1.FakeLabelsV1alpha1{Fake: &c.Fake}
 }
 
-// Labels retrieves the LabelsV1alpha1Client
-func (c *Clientset) Labels() labelsv1alpha1.LabelsV1alpha1Interface {
-	return &fakelabelsv1alpha1.FakeLabelsV1alpha1{Fake: &c.Fake}
+// LabelsV1beta1 retrieves the LabelsV1beta1Client
+func (c *Clientset) LabelsV1beta1() labelsv1beta1.LabelsV1beta1Interface {
+	return &fakelabelsv1beta1.FakeLabelsV1beta1{Fake: &c.Fake}
 }
@@ -17,7 +17,12 @@
 package fake
 
 import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
 	v1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	nodepoollabelsetv1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/applyconfiguration/nodepoollabelset/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -37,7 +42,7 @@ var nodepoollabelsetsResource = schema.GroupVersionResource{Group: "labels.banza
 var nodepoollabelsetsKind = schema.GroupVersionKind{Group: "labels.banzaicloud.io", Version: "v1alpha1", Kind: "NodePoolLabelSet"}
 
 // Get takes name of the nodePoolLabelSet, and returns the corresponding nodePoolLabelSet object, and an error if there is any.
-func (c *FakeNodePoolLabelSets) Get(name string, options v1.GetOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
+func (c *FakeNodePoolLabelSets) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewGetAction(nodepoollabelsetsResource, c.ns, name), &v1alpha1.NodePoolLabelSet{})
 
@@ -48,7 +53,7 @@ func (c *FakeNodePoolLabelSets) Get(name string, options v1.GetOptions) (result
 }
 
 // List takes label and field selectors, and returns the list of NodePoolLabelSets that match those selectors.
-func (c *FakeNodePoolLabelSets) List(opts v1.ListOptions) (result *v1alpha1.NodePoolLabelSetList, err error) {
+func (c *FakeNodePoolLabelSets) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.NodePoolLabelSetList, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewListAction(nodepoollabelsetsResource, nodepoollabelsetsKind, c.ns, opts), &v1alpha1.NodePoolLabelSetList{})
 
@@ -70,14 +75,14 @@ func (c *FakeNodePoolLabelSets) List(opts v1.ListOptions) (result *v1alpha1.Node
 }
 
 // Watch returns a watch.Interface that watches the requested nodePoolLabelSets.
-func (c *FakeNodePoolLabelSets) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *FakeNodePoolLabelSets) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
 	return c.Fake.
 		InvokesWatch(testing.NewWatchAction(nodepoollabelsetsResource, c.ns, opts))
 
 }
 
 // Create takes the representation of a nodePoolLabelSet and creates it.  Returns the server's representation of the nodePoolLabelSet, and an error, if there is any.
-func (c *FakeNodePoolLabelSets) Create(nodePoolLabelSet *v1alpha1.NodePoolLabelSet) (result *v1alpha1.NodePoolLabelSet, err error) {
+func (c *FakeNodePoolLabelSets) Create(ctx context.Context, nodePoolLabelSet *v1alpha1.NodePoolLabelSet, opts v1.CreateOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewCreateAction(nodepoollabelsetsResource, c.ns, nodePoolLabelSet), &v1alpha1.NodePoolLabelSet{})
 
@@ -88,7 +93,7 @@ func (c *FakeNodePoolLabelSets) Create(nodePoolLabelSet *v1alpha1.NodePoolLabelS
 }
 
 // Update takes the representation of a nodePoolLabelSet and updates it. Returns the server's representation of the nodePoolLabelSet, and an error, if there is any.
-func (c *FakeNodePoolLabelSets) Update(nodePoolLabelSet *v1alpha1.NodePoolLabelSet) (result *v1alpha1.NodePoolLabelSet, err error) {
+func (c *FakeNodePoolLabelSets) Update(ctx context.Context, nodePoolLabelSet *v1alpha1.NodePoolLabelSet, opts v1.UpdateOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewUpdateAction(nodepoollabelsetsResource, c.ns, nodePoolLabelSet), &v1alpha1.NodePoolLabelSet{})
 
@@ -98,8 +103,20 @@ func (c *FakeNodePoolLabelSets) Update(nodePoolLabelSet *v1alpha1.NodePoolLabelS
 	return obj.(*v1alpha1.NodePoolLabelSet), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNodePoolLabelSets) UpdateStatus(ctx context.Context, nodePoolLabelSet *v1alpha1.NodePoolLabelSet, opts v1.UpdateOptions) (*v1alpha1.NodePoolLabelSet, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(nodepoollabelsetsResource, "status", c.ns, nodePoolLabelSet), &v1alpha1.NodePoolLabelSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NodePoolLabelSet), err
+}
+
 // Delete takes name of the nodePoolLabelSet and deletes it. Returns an error if one occurs.
-func (c *FakeNodePoolLabelSets) Delete(name string, options *v1.DeleteOptions) error {
+func (c *FakeNodePoolLabelSets) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
 		Invokes(testing.NewDeleteAction(nodepoollabelsetsResource, c.ns, name), &v1alpha1.NodePoolLabelSet{})
 
@@ -107,15 +124,15 @@ func (c *FakeNodePoolLabelSets) Delete(name string, options *v1.DeleteOptions) e
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *FakeNodePoolLabelSets) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
-	action := testing.NewDeleteCollectionAction(nodepoollabelsetsResource, c.ns, listOptions)
+func (c *FakeNodePoolLabelSets) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(nodepoollabelsetsResource, c.ns, listOpts)
 
 	_, err := c.Fake.Invokes(action, &v1alpha1.NodePoolLabelSetList{})
 	return err
 }
 
 // Patch applies the patch and returns the patched nodePoolLabelSet.
-func (c *FakeNodePoolLabelSets) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.NodePoolLabelSet, err error) {
+func (c *FakeNodePoolLabelSets) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.NodePoolLabelSet, err error) {
 	obj, err := c.Fake.
 		Invokes(testing.NewPatchSubresourceAction(nodepoollabelsetsResource, c.ns, name, pt, data, subresources...), &v1alpha1.NodePoolLabelSet{})
 
@@ -124,3 +141,48 @@ func (c *FakeNodePoolLabelSets) Patch(name string, pt types.PatchType, data []by
 	}
 	return obj.(*v1alpha1.NodePoolLabelSet), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied nodePoolLabelSet.
+func (c *FakeNodePoolLabelSets) Apply(ctx context.Context, nodePoolLabelSet *nodepoollabelsetv1alpha1.NodePoolLabelSetApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
+	if nodePoolLabelSet == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(nodePoolLabelSet)
+	if err != nil {
+		return nil, err
+	}
+	name := nodePoolLabelSet.Name
+	if name == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(nodepoollabelsetsResource, c.ns, *name, types.ApplyPatchType, data), &v1alpha1.NodePoolLabelSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NodePoolLabelSet), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeNodePoolLabelSets) ApplyStatus(ctx context.Context, nodePoolLabelSet *nodepoollabelsetv1alpha1.NodePoolLabelSetApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
+	if nodePoolLabelSet == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(nodePoolLabelSet)
+	if err != nil {
+		return nil, err
+	}
+	name := nodePoolLabelSet.Name
+	if name == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(nodepoollabelsetsResource, c.ns, *name, types.ApplyPatchType, data, "status"), &v1alpha1.NodePoolLabelSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NodePoolLabelSet), err
+}
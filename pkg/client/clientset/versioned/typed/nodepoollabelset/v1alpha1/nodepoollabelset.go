@@ -18,8 +18,12 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
+	"time"
 
 	v1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	nodepoollabelsetv1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/applyconfiguration/nodepoollabelset/v1alpha1"
 	scheme "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/scheme"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
@@ -35,14 +39,17 @@ type NodePoolLabelSetsGetter interface {
 
 // NodePoolLabelSetInterface has methods to work with NodePoolLabelSet resources.
 type NodePoolLabelSetInterface interface {
-	Create(*v1alpha1.NodePoolLabelSet) (*v1alpha1.NodePoolLabelSet, error)
-	Update(*v1alpha1.NodePoolLabelSet) (*v1alpha1.NodePoolLabelSet, error)
-	Delete(name string, options *v1.DeleteOptions) error
-	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
-	Get(name string, options v1.GetOptions) (*v1alpha1.NodePoolLabelSet, error)
-	List(opts v1.ListOptions) (*v1alpha1.NodePoolLabelSetList, error)
-	Watch(opts v1.ListOptions) (watch.Interface, error)
-	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.NodePoolLabelSet, err error)
+	Create(ctx context.Context, nodePoolLabelSet *v1alpha1.NodePoolLabelSet, opts v1.CreateOptions) (*v1alpha1.NodePoolLabelSet, error)
+	Update(ctx context.Context, nodePoolLabelSet *v1alpha1.NodePoolLabelSet, opts v1.UpdateOptions) (*v1alpha1.NodePoolLabelSet, error)
+	UpdateStatus(ctx context.Context, nodePoolLabelSet *v1alpha1.NodePoolLabelSet, opts v1.UpdateOptions) (*v1alpha1.NodePoolLabelSet, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.NodePoolLabelSet, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.NodePoolLabelSetList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.NodePoolLabelSet, err error)
+	Apply(ctx context.Context, nodePoolLabelSet *nodepoollabelsetv1alpha1.NodePoolLabelSetApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NodePoolLabelSet, err error)
+	ApplyStatus(ctx context.Context, nodePoolLabelSet *nodepoollabelsetv1alpha1.NodePoolLabelSetApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NodePoolLabelSet, err error)
 	NodePoolLabelSetExpansion
 }
 
@@ -61,97 +68,187 @@ func newNodePoolLabelSets(c *LabelsV1alpha1Client, namespace string) *nodePoolLa
 }
 
 // Get takes name of the nodePoolLabelSet, and returns the corresponding nodePoolLabelSet object, and an error if there is any.
-func (c *nodePoolLabelSets) Get(name string, options v1.GetOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
+func (c *nodePoolLabelSets) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
 	result = &v1alpha1.NodePoolLabelSet{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("nodepoollabelsets").
 		Name(name).
 		VersionedParams(&options, scheme.ParameterCodec).
-		Do(context.TODO()).
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // List takes label and field selectors, and returns the list of NodePoolLabelSets that match those selectors.
-func (c *nodePoolLabelSets) List(opts v1.ListOptions) (result *v1alpha1.NodePoolLabelSetList, err error) {
+func (c *nodePoolLabelSets) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.NodePoolLabelSetList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
 	result = &v1alpha1.NodePoolLabelSetList{}
 	err = c.client.Get().
 		Namespace(c.ns).
 		Resource("nodepoollabelsets").
 		VersionedParams(&opts, scheme.ParameterCodec).
-		Do(context.TODO()).
+		Timeout(timeout).
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Watch returns a watch.Interface that watches the requested nodePoolLabelSets.
-func (c *nodePoolLabelSets) Watch(opts v1.ListOptions) (watch.Interface, error) {
+func (c *nodePoolLabelSets) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
 	opts.Watch = true
 	return c.client.Get().
 		Namespace(c.ns).
 		Resource("nodepoollabelsets").
 		VersionedParams(&opts, scheme.ParameterCodec).
-		Watch(context.TODO())
+		Timeout(timeout).
+		Watch(ctx)
 }
 
 // Create takes the representation of a nodePoolLabelSet and creates it.  Returns the server's representation of the nodePoolLabelSet, and an error, if there is any.
-func (c *nodePoolLabelSets) Create(nodePoolLabelSet *v1alpha1.NodePoolLabelSet) (result *v1alpha1.NodePoolLabelSet, err error) {
+func (c *nodePoolLabelSets) Create(ctx context.Context, nodePoolLabelSet *v1alpha1.NodePoolLabelSet, opts v1.CreateOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
 	result = &v1alpha1.NodePoolLabelSet{}
 	err = c.client.Post().
 		Namespace(c.ns).
 		Resource("nodepoollabelsets").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(nodePoolLabelSet).
-		Do(context.TODO()).
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Update takes the representation of a nodePoolLabelSet and updates it. Returns the server's representation of the nodePoolLabelSet, and an error, if there is any.
-func (c *nodePoolLabelSets) Update(nodePoolLabelSet *v1alpha1.NodePoolLabelSet) (result *v1alpha1.NodePoolLabelSet, err error) {
+func (c *nodePoolLabelSets) Update(ctx context.Context, nodePoolLabelSet *v1alpha1.NodePoolLabelSet, opts v1.UpdateOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
+	result = &v1alpha1.NodePoolLabelSet{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("nodepoollabelsets").
+		Name(nodePoolLabelSet.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(nodePoolLabelSet).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *nodePoolLabelSets) UpdateStatus(ctx context.Context, nodePoolLabelSet *v1alpha1.NodePoolLabelSet, opts v1.UpdateOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
 	result = &v1alpha1.NodePoolLabelSet{}
 	err = c.client.Put().
 		Namespace(c.ns).
 		Resource("nodepoollabelsets").
 		Name(nodePoolLabelSet.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
 		Body(nodePoolLabelSet).
-		Do(context.TODO()).
+		Do(ctx).
 		Into(result)
 	return
 }
 
 // Delete takes name of the nodePoolLabelSet and deletes it. Returns an error if one occurs.
-func (c *nodePoolLabelSets) Delete(name string, options *v1.DeleteOptions) error {
+func (c *nodePoolLabelSets) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("nodepoollabelsets").
 		Name(name).
-		Body(options).
-		Do(context.TODO()).
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // DeleteCollection deletes a collection of objects.
-func (c *nodePoolLabelSets) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+func (c *nodePoolLabelSets) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
 	return c.client.Delete().
 		Namespace(c.ns).
 		Resource("nodepoollabelsets").
-		VersionedParams(&listOptions, scheme.ParameterCodec).
-		Body(options).
-		Do(context.TODO()).
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
 		Error()
 }
 
 // Patch applies the patch and returns the patched nodePoolLabelSet.
-func (c *nodePoolLabelSets) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.NodePoolLabelSet, err error) {
+func (c *nodePoolLabelSets) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.NodePoolLabelSet, err error) {
 	result = &v1alpha1.NodePoolLabelSet{}
 	err = c.client.Patch(pt).
 		Namespace(c.ns).
 		Resource("nodepoollabelsets").
-		SubResource(subresources...).
 		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied nodePoolLabelSet.
+func (c *nodePoolLabelSets) Apply(ctx context.Context, nodePoolLabelSet *nodepoollabelsetv1alpha1.NodePoolLabelSetApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
+	if nodePoolLabelSet == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(nodePoolLabelSet)
+	if err != nil {
+		return nil, err
+	}
+	name := nodePoolLabelSet.Name
+	if name == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet.Name must be provided to Apply")
+	}
+	result = &v1alpha1.NodePoolLabelSet{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("nodepoollabelsets").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *nodePoolLabelSets) ApplyStatus(ctx context.Context, nodePoolLabelSet *nodepoollabelsetv1alpha1.NodePoolLabelSetApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.NodePoolLabelSet, err error) {
+	if nodePoolLabelSet == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(nodePoolLabelSet)
+	if err != nil {
+		return nil, err
+	}
+
+	name := nodePoolLabelSet.Name
+	if name == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.NodePoolLabelSet{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("nodepoollabelsets").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
 		Body(data).
-		Do(context.TODO()).
+		Do(ctx).
 		Into(result)
 	return
 }
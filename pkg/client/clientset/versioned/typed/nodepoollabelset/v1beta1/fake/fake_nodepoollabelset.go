@@ -0,0 +1,188 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1beta1"
+	nodepoollabelsetv1beta1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/applyconfiguration/nodepoollabelset/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeNodePoolLabelSets implements NodePoolLabelSetInterface
+type FakeNodePoolLabelSets struct {
+	Fake *FakeLabelsV1beta1
+	ns   string
+}
+
+var nodepoollabelsetsResource = schema.GroupVersionResource{Group: "labels.banzaicloud.io", Version: "v1beta1", Resource: "nodepoollabelsets"}
+
+var nodepoollabelsetsKind = schema.GroupVersionKind{Group: "labels.banzaicloud.io", Version: "v1beta1", Kind: "NodePoolLabelSet"}
+
+// Get takes name of the nodePoolLabelSet, and returns the corresponding nodePoolLabelSet object, and an error if there is any.
+func (c *FakeNodePoolLabelSets) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.NodePoolLabelSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(nodepoollabelsetsResource, c.ns, name), &v1beta1.NodePoolLabelSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.NodePoolLabelSet), err
+}
+
+// List takes label and field selectors, and returns the list of NodePoolLabelSets that match those selectors.
+func (c *FakeNodePoolLabelSets) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.NodePoolLabelSetList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(nodepoollabelsetsResource, nodepoollabelsetsKind, c.ns, opts), &v1beta1.NodePoolLabelSetList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.NodePoolLabelSetList{ListMeta: obj.(*v1beta1.NodePoolLabelSetList).ListMeta}
+	for _, item := range obj.(*v1beta1.NodePoolLabelSetList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested nodePoolLabelSets.
+func (c *FakeNodePoolLabelSets) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(nodepoollabelsetsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a nodePoolLabelSet and creates it.  Returns the server's representation of the nodePoolLabelSet, and an error, if there is any.
+func (c *FakeNodePoolLabelSets) Create(ctx context.Context, nodePoolLabelSet *v1beta1.NodePoolLabelSet, opts v1.CreateOptions) (result *v1beta1.NodePoolLabelSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(nodepoollabelsetsResource, c.ns, nodePoolLabelSet), &v1beta1.NodePoolLabelSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.NodePoolLabelSet), err
+}
+
+// Update takes the representation of a nodePoolLabelSet and updates it. Returns the server's representation of the nodePoolLabelSet, and an error, if there is any.
+func (c *FakeNodePoolLabelSets) Update(ctx context.Context, nodePoolLabelSet *v1beta1.NodePoolLabelSet, opts v1.UpdateOptions) (result *v1beta1.NodePoolLabelSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(nodepoollabelsetsResource, c.ns, nodePoolLabelSet), &v1beta1.NodePoolLabelSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.NodePoolLabelSet), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNodePoolLabelSets) UpdateStatus(ctx context.Context, nodePoolLabelSet *v1beta1.NodePoolLabelSet, opts v1.UpdateOptions) (*v1beta1.NodePoolLabelSet, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(nodepoollabelsetsResource, "status", c.ns, nodePoolLabelSet), &v1beta1.NodePoolLabelSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.NodePoolLabelSet), err
+}
+
+// Delete takes name of the nodePoolLabelSet and deletes it. Returns an error if one occurs.
+func (c *FakeNodePoolLabelSets) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(nodepoollabelsetsResource, c.ns, name), &v1beta1.NodePoolLabelSet{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeNodePoolLabelSets) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(nodepoollabelsetsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1beta1.NodePoolLabelSetList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched nodePoolLabelSet.
+func (c *FakeNodePoolLabelSets) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.NodePoolLabelSet, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(nodepoollabelsetsResource, c.ns, name, pt, data, subresources...), &v1beta1.NodePoolLabelSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.NodePoolLabelSet), err
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied nodePoolLabelSet.
+func (c *FakeNodePoolLabelSets) Apply(ctx context.Context, nodePoolLabelSet *nodepoollabelsetv1beta1.NodePoolLabelSetApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.NodePoolLabelSet, err error) {
+	if nodePoolLabelSet == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(nodePoolLabelSet)
+	if err != nil {
+		return nil, err
+	}
+	name := nodePoolLabelSet.Name
+	if name == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(nodepoollabelsetsResource, c.ns, *name, types.ApplyPatchType, data), &v1beta1.NodePoolLabelSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.NodePoolLabelSet), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeNodePoolLabelSets) ApplyStatus(ctx context.Context, nodePoolLabelSet *nodepoollabelsetv1beta1.NodePoolLabelSetApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.NodePoolLabelSet, err error) {
+	if nodePoolLabelSet == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(nodePoolLabelSet)
+	if err != nil {
+		return nil, err
+	}
+	name := nodePoolLabelSet.Name
+	if name == nil {
+		return nil, fmt.Errorf("nodePoolLabelSet.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(nodepoollabelsetsResource, c.ns, *name, types.ApplyPatchType, data, "status"), &v1beta1.NodePoolLabelSet{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.NodePoolLabelSet), err
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	v1beta1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -54,6 +55,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1alpha1.SchemeGroupVersion.WithResource("nodepoollabelsets"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Labels().V1alpha1().NodePoolLabelSets().Informer()}, nil
 
+		// Group=labels.banzaicloud.io, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("nodepoollabelsets"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Labels().V1beta1().NodePoolLabelSets().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
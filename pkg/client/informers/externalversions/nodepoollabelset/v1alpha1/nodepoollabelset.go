@@ -17,9 +17,10 @@
 package v1alpha1
 
 import (
+	"context"
 	time "time"
 
-	nodepoollabelset_v1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	nodepoollabelsetv1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
 	versioned "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned"
 	internalinterfaces "github.com/banzaicloud/nodepool-labels-operator/pkg/client/informers/externalversions/internalinterfaces"
 	v1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/listers/nodepoollabelset/v1alpha1"
@@ -59,16 +60,16 @@ func NewFilteredNodePoolLabelSetInformer(client versioned.Interface, namespace s
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.LabelsV1alpha1().NodePoolLabelSets(namespace).List(options)
+				return client.LabelsV1alpha1().NodePoolLabelSets(namespace).List(context.TODO(), options)
 			},
 			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
 				if tweakListOptions != nil {
 					tweakListOptions(&options)
 				}
-				return client.LabelsV1alpha1().NodePoolLabelSets(namespace).Watch(options)
+				return client.LabelsV1alpha1().NodePoolLabelSets(namespace).Watch(context.TODO(), options)
 			},
 		},
-		&nodepoollabelset_v1alpha1.NodePoolLabelSet{},
+		&nodepoollabelsetv1alpha1.NodePoolLabelSet{},
 		resyncPeriod,
 		indexers,
 	)
@@ -79,7 +80,7 @@ func (f *nodePoolLabelSetInformer) defaultInformer(client versioned.Interface, r
 }
 
 func (f *nodePoolLabelSetInformer) Informer() cache.SharedIndexInformer {
-	return f.factory.InformerFor(&nodepoollabelset_v1alpha1.NodePoolLabelSet{}, f.defaultInformer)
+	return f.factory.InformerFor(&nodepoollabelsetv1alpha1.NodePoolLabelSet{}, f.defaultInformer)
 }
 
 func (f *nodePoolLabelSetInformer) Lister() v1alpha1.NodePoolLabelSetLister {
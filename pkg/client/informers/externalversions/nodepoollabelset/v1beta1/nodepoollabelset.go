@@ -0,0 +1,88 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	time "time"
+
+	nodepoollabelsetv1beta1 "github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1beta1"
+	versioned "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/banzaicloud/nodepool-labels-operator/pkg/client/informers/externalversions/internalinterfaces"
+	v1beta1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/listers/nodepoollabelset/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// NodePoolLabelSetInformer provides access to a shared informer and lister for
+// NodePoolLabelSets.
+type NodePoolLabelSetInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1beta1.NodePoolLabelSetLister
+}
+
+type nodePoolLabelSetInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewNodePoolLabelSetInformer constructs a new informer for NodePoolLabelSet type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewNodePoolLabelSetInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredNodePoolLabelSetInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredNodePoolLabelSetInformer constructs a new informer for NodePoolLabelSet type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredNodePoolLabelSetInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.LabelsV1beta1().NodePoolLabelSets(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.LabelsV1beta1().NodePoolLabelSets(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&nodepoollabelsetv1beta1.NodePoolLabelSet{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *nodePoolLabelSetInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredNodePoolLabelSetInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *nodePoolLabelSetInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&nodepoollabelsetv1beta1.NodePoolLabelSet{}, f.defaultInformer)
+}
+
+func (f *nodePoolLabelSetInformer) Lister() v1beta1.NodePoolLabelSetLister {
+	return v1beta1.NewNodePoolLabelSetLister(f.Informer().GetIndexer())
+}
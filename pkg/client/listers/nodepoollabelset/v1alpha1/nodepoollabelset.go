@@ -24,8 +24,10 @@ import (
 )
 
 // NodePoolLabelSetLister helps list NodePoolLabelSets.
+// All objects returned here must be treated as read-only.
 type NodePoolLabelSetLister interface {
 	// List lists all NodePoolLabelSets in the indexer.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1alpha1.NodePoolLabelSet, err error)
 	// NodePoolLabelSets returns an object that can list and get NodePoolLabelSets.
 	NodePoolLabelSets(namespace string) NodePoolLabelSetNamespaceLister
@@ -56,10 +58,13 @@ func (s *nodePoolLabelSetLister) NodePoolLabelSets(namespace string) NodePoolLab
 }
 
 // NodePoolLabelSetNamespaceLister helps list and get NodePoolLabelSets.
+// All objects returned here must be treated as read-only.
 type NodePoolLabelSetNamespaceLister interface {
 	// List lists all NodePoolLabelSets in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
 	List(selector labels.Selector) (ret []*v1alpha1.NodePoolLabelSet, err error)
 	// Get retrieves the NodePoolLabelSet from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
 	Get(name string) (*v1alpha1.NodePoolLabelSet, error)
 	NodePoolLabelSetNamespaceListerExpansion
 }
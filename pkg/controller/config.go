@@ -14,10 +14,163 @@
 
 package controller
 
+import (
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
 type Config struct {
 	// Namespace is where the labeler looks for NPLS resources
 	Namespace string `mapstructure:"namespace"`
 	// NodepoolNameLabels contains label names which are used in order
 	// to try to determine the nodepool name the node is part of
 	NodepoolNameLabels []string `mapstructure:"nodepoolNameLabels"`
+	// StartupTaint configures the removal of the startup taint after the labels are applied
+	StartupTaint StartupTaintConfig `mapstructure:"startupTaint"`
+	// Workers is the number of workers processing the workqueue
+	Workers int `mapstructure:"workers"`
+	// Queue configures the rate limits and the retry policy of the workqueue
+	Queue QueueConfig `mapstructure:"queue"`
+	// Client configures the rate limits and the timeout of the k8s API client
+	Client ClientConfig `mapstructure:"client"`
+	// ShutdownGracePeriod is the time in-flight work is given to finish before it gets cancelled
+	ShutdownGracePeriod time.Duration `mapstructure:"shutdownGracePeriod"`
+	// StuckWorkerThreshold is the time without workqueue progress while items are
+	// pending after which the controller is reported as not live
+	StuckWorkerThreshold time.Duration `mapstructure:"stuckWorkerThreshold"`
+}
+
+type QueueConfig struct {
+	// BaseDelay is the initial backoff of a failed item
+	BaseDelay time.Duration `mapstructure:"baseDelay"`
+	// MaxDelay is the maximum backoff of a failed item
+	MaxDelay time.Duration `mapstructure:"maxDelay"`
+	// QPS is the overall rate of requeued items
+	QPS float64 `mapstructure:"qps"`
+	// Burst is the overall burst of requeued items
+	Burst int `mapstructure:"burst"`
+	// MaxRetries is the number of retries after which an item is dropped, zero means no limit
+	MaxRetries int `mapstructure:"maxRetries"`
+}
+
+type ClientConfig struct {
+	// QPS is the maximum queries per second to the k8s API server
+	QPS float32 `mapstructure:"qps"`
+	// Burst is the maximum burst of queries to the k8s API server
+	Burst int `mapstructure:"burst"`
+	// Timeout limits the API calls made while processing a single workqueue item, zero means no limit
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+type StartupTaintConfig struct {
+	// Enabled turns on the removal of the startup taint
+	Enabled bool `mapstructure:"enabled"`
+	// Key is the key of the taint which is registered via kubelet --register-with-taints
+	Key string `mapstructure:"key"`
+	// Timeout is the age of a node after which the taint is removed even if the labels could not be applied
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// Validate checks that the configuration is valid.
+func (c Config) Validate() error {
+	if c.Namespace == "" {
+		return errors.New("namespace must not be empty")
+	}
+
+	if errs := validation.IsDNS1123Label(c.Namespace); len(errs) > 0 {
+		return errors.Errorf("invalid namespace %q: %s", c.Namespace, strings.Join(errs, ", "))
+	}
+
+	if len(c.NodepoolNameLabels) == 0 {
+		return errors.New("nodepool name labels must not be empty")
+	}
+
+	for _, label := range c.NodepoolNameLabels {
+		if errs := validation.IsQualifiedName(label); len(errs) > 0 {
+			return errors.Errorf("invalid nodepool name label %q: %s", label, strings.Join(errs, ", "))
+		}
+	}
+
+	err := c.StartupTaint.Validate()
+	if err != nil {
+		return errors.WrapIf(err, "could not validate startup taint config")
+	}
+
+	if c.Workers < 0 {
+		return errors.New("workers must not be negative")
+	}
+
+	err = c.Queue.Validate()
+	if err != nil {
+		return errors.WrapIf(err, "could not validate queue config")
+	}
+
+	err = c.Client.Validate()
+	if err != nil {
+		return errors.WrapIf(err, "could not validate client config")
+	}
+
+	if c.ShutdownGracePeriod < 0 {
+		return errors.New("shutdown grace period must not be negative")
+	}
+
+	if c.StuckWorkerThreshold < 0 {
+		return errors.New("stuck worker threshold must not be negative")
+	}
+
+	return nil
+}
+
+// Validate checks that the configuration is valid.
+func (c QueueConfig) Validate() error {
+	if c.BaseDelay < 0 || c.MaxDelay < 0 {
+		return errors.New("delays must not be negative")
+	}
+
+	if c.BaseDelay > 0 && c.MaxDelay > 0 && c.BaseDelay > c.MaxDelay {
+		return errors.New("base delay must not be greater than max delay")
+	}
+
+	if c.QPS < 0 || c.Burst < 0 {
+		return errors.New("qps and burst must not be negative")
+	}
+
+	if c.MaxRetries < 0 {
+		return errors.New("max retries must not be negative")
+	}
+
+	return nil
+}
+
+// Validate checks that the configuration is valid.
+func (c ClientConfig) Validate() error {
+	if c.QPS < 0 || c.Burst < 0 {
+		return errors.New("qps and burst must not be negative")
+	}
+
+	if c.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+
+	return nil
+}
+
+// Validate checks that the configuration is valid.
+func (c StartupTaintConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if errs := validation.IsQualifiedName(c.Key); len(errs) > 0 {
+		return errors.Errorf("invalid taint key %q: %s", c.Key, strings.Join(errs, ", "))
+	}
+
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+
+	return nil
 }
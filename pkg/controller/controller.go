@@ -16,44 +16,96 @@ package controller
 
 import (
 	"context"
-	"os"
-	"os/signal"
-	"syscall"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"emperror.dev/emperror"
 	"emperror.dev/errors"
+	"golang.org/x/time/rate"
 	api_v1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	k8sinformers "k8s.io/client-go/informers"
 	corev1 "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typed_corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
 	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
 	npls_clientset "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned"
+	npls_informers "github.com/banzaicloud/nodepool-labels-operator/pkg/client/informers/externalversions"
 	informers "github.com/banzaicloud/nodepool-labels-operator/pkg/client/informers/externalversions/nodepoollabelset/v1alpha1"
 	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/npls"
+)
+
+const (
+	componentName = "nodepool-labels-operator"
+
+	defaultWorkers        = 10
+	defaultQueueBaseDelay = 5 * time.Millisecond
+	defaultQueueMaxDelay  = 1000 * time.Second
+	defaultQueueQPS       = 10
+	defaultQueueBurst     = 100
+
+	defaultShutdownGracePeriod = 30 * time.Second
+
+	// syncStateRecheckDelay is how often a syncing node pool without a rollout
+	// strategy is checked whether its nodes are synced
+	syncStateRecheckDelay = 10 * time.Second
+
+	invalidLabelsReason = "InvalidLabels"
 )
 
 // Controller manages node pool labels
 type Controller struct {
-	namespace          string
+	namespace string
+
+	// nodepoolNameLabels can be changed at runtime
+	mu                 sync.RWMutex
 	nodepoolNameLabels []string
 
+	startupTaint StartupTaintConfig
+	workers      int
+	maxRetries   int
+	timeout      time.Duration
+
+	shutdownGracePeriod  time.Duration
+	stuckWorkerThreshold time.Duration
+
+	// rolloutBatches are the last batches of the rollouts by NPLS key
+	rolloutMu      sync.Mutex
+	rolloutBatches map[string]rolloutBatch
+
+	// accessed atomically
+	running        int32
+	idleWorkers    int32
+	lastProgress   int64
+	lastAPISuccess *int64
+
 	k8sConfig *rest.Config
 	labeler   *labeler.Labeler
 
-	nodeInformer  corev1.NodeInformer
-	nplsInformer  informers.NodePoolLabelSetInformer
-	workqueue     workqueue.RateLimitingInterface
+	nodeInformerFactory k8sinformers.SharedInformerFactory
+	nodeInformer        corev1.NodeInformer
+	nplsInformerFactory npls_informers.SharedInformerFactory
+	nplsInformer        informers.NodePoolLabelSetInformer
+
+	workqueue     *inspectableQueue
 	clientset     kubernetes.Interface
 	nplsClientset npls_clientset.Interface
+	recorder      record.EventRecorder
 
 	logger       log.Logger
 	errorHandler emperror.Handler
@@ -61,6 +113,9 @@ type Controller struct {
 
 // New gives back an initialized Controller
 func New(config Config, k8sConfig *rest.Config, labeler *labeler.Labeler, logger log.Logger, errorHandler emperror.Handler) (*Controller, error) {
+	lastAPISuccess := new(int64)
+	k8sConfig = withAPISuccessRecorder(k8sConfig, lastAPISuccess)
+
 	clientset, err := kubernetes.NewForConfig(k8sConfig)
 	if err != nil {
 		return nil, errors.WrapIf(err, "could not get k8s clientset")
@@ -71,101 +126,210 @@ func New(config Config, k8sConfig *rest.Config, labeler *labeler.Labeler, logger
 		return nil, errors.WrapIf(err, "could not get k8s npls clientset")
 	}
 
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typed_corev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	utilruntime.Must(v1alpha1.AddToScheme(scheme.Scheme))
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, api_v1.EventSource{Component: componentName})
+
+	shutdownGracePeriod := config.ShutdownGracePeriod
+	if shutdownGracePeriod <= 0 {
+		shutdownGracePeriod = defaultShutdownGracePeriod
+	}
+
+	stuckWorkerThreshold := config.StuckWorkerThreshold
+	if stuckWorkerThreshold <= 0 {
+		stuckWorkerThreshold = defaultStuckWorkerThreshold
+	}
+
+	queue := newInspectableQueue(newRateLimiter(config.Queue))
+	informerLogger := logger.Component("informers")
+	nodeInformerFactory, nodeInformer := GetNodeInformer(clientset, 0, queue, informerLogger)
+	nplsInformerFactory, nplsInformer := GetNPLSInformer(nplsClientset, 0, queue, informerLogger)
+
 	return &Controller{
 		k8sConfig: k8sConfig,
 		labeler:   labeler,
 
 		namespace:          config.Namespace,
 		nodepoolNameLabels: config.NodepoolNameLabels,
+		startupTaint:       config.StartupTaint,
+		workers:            config.Workers,
+		maxRetries:         config.Queue.MaxRetries,
+		timeout:            config.Client.Timeout,
+
+		shutdownGracePeriod:  shutdownGracePeriod,
+		stuckWorkerThreshold: stuckWorkerThreshold,
+		lastAPISuccess:       lastAPISuccess,
+
+		nodeInformerFactory: nodeInformerFactory,
+		nodeInformer:        nodeInformer,
+		nplsInformerFactory: nplsInformerFactory,
+		nplsInformer:        nplsInformer,
 
-		workqueue:     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		workqueue:     queue,
 		clientset:     clientset,
 		nplsClientset: nplsClientset,
+		recorder:      recorder,
 
 		logger:       logger,
 		errorHandler: errorHandler,
 	}, nil
 }
 
-// Start initializes the informers and starts observing them
-func (c *Controller) Start() error {
-	stopCh := make(chan struct{})
-	defer close(stopCh)
+func newRateLimiter(config QueueConfig) workqueue.RateLimiter {
+	baseDelay := config.BaseDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultQueueBaseDelay
+	}
+	maxDelay := config.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultQueueMaxDelay
+	}
+	qps := config.QPS
+	if qps <= 0 {
+		qps = defaultQueueQPS
+	}
+	burst := config.Burst
+	if burst <= 0 {
+		burst = defaultQueueBurst
+	}
 
-	nodeInformerFactory, nodeInformer := GetNodeInformer(c.clientset, 0, c.workqueue)
-	nodeInformerFactory.Start(stopCh)
-	c.nodeInformer = nodeInformer
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
 
-	nplsInformerFactory, nplsInformer := GetNPLSInformer(c.nplsClientset, 0, c.workqueue)
-	nplsInformerFactory.Start(stopCh)
-	c.nplsInformer = nplsInformer
+// Start initializes the informers and starts observing them, it blocks until
+// the context is cancelled and every worker is stopped
+func (c *Controller) Start(ctx context.Context) error {
+	c.nodeInformerFactory.Start(ctx.Done())
+	c.nplsInformerFactory.Start(ctx.Done())
 
-	err := c.run(10, stopCh)
+	workers := c.workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	err := c.run(ctx, workers)
 	if err != nil {
 		return errors.WrapIf(err, "could not observe")
 	}
 
-	sigterm := make(chan os.Signal, 1)
-	signal.Notify(sigterm, syscall.SIGTERM)
-	signal.Notify(sigterm, syscall.SIGINT)
-	<-sigterm
-
 	return nil
 }
 
 // Run start observing the informers and processing events
-func (c *Controller) run(threadiness int, stopCh <-chan struct{}) error {
+func (c *Controller) run(ctx context.Context, threadiness int) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
 
 	c.logger.Info("starting NPLS resource controller")
 
 	c.logger.Info("waiting for informer caches to sync")
-	if ok := cache.WaitForCacheSync(stopCh, c.nodeInformer.Informer().HasSynced, c.nplsInformer.Informer().HasSynced); !ok {
+	if ok := cache.WaitForCacheSync(ctx.Done(), c.nodeInformer.Informer().HasSynced, c.nplsInformer.Informer().HasSynced); !ok {
+		if ctx.Err() != nil {
+			return nil
+		}
 		return errors.New("failed to wait for caches to sync")
 	}
 
+	// in-flight work is only cancelled when the shutdown grace period is over,
+	// the workers stop taking new items as soon as ctx is done
+	workCtx, cancelWork := context.WithCancel(context.Background())
+	defer cancelWork()
+
 	c.logger.Info("starting workers")
+	var wg sync.WaitGroup
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait.Until(func() { c.runWorker(ctx, workCtx) }, time.Second, ctx.Done())
+		}()
 	}
+	c.recordProgress()
+	atomic.StoreInt32(&c.running, 1)
 	c.logger.Info("workers started")
 
-	<-stopCh
+	<-ctx.Done()
 	c.logger.Info("shutting down workers")
+	atomic.StoreInt32(&c.running, 0)
+	c.workqueue.ShutDown()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(c.shutdownGracePeriod):
+		c.logger.Warn("shutdown grace period is over, cancelling in-flight work")
+		cancelWork()
+		<-done
+	}
+	c.logger.Info("workers stopped")
 
 	return nil
 }
 
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(stopCtx context.Context, ctx context.Context) {
+	for c.processNextWorkItem(stopCtx, ctx) {
 	}
 }
 
-func (c *Controller) processNextWorkItem() bool {
+// processNextWorkItem processes the next item of the workqueue with ctx, it
+// gives back false once the workqueue is shut down or stopCtx is done
+func (c *Controller) processNextWorkItem(stopCtx context.Context, ctx context.Context) bool {
+	atomic.AddInt32(&c.idleWorkers, 1)
 	obj, shutdown := c.workqueue.Get()
+	atomic.AddInt32(&c.idleWorkers, -1)
 
 	if shutdown {
 		return false
 	}
 
+	// the items still queued on shutdown are dropped, every node and node pool
+	// is reconciled again on the next start
+	if stopCtx.Err() != nil {
+		c.workqueue.Done(obj)
+		return false
+	}
+	c.recordProgress()
+
 	err := func(obj interface{}) error {
+		defer c.recordProgress()
 		defer c.workqueue.Done(obj)
-		var event *Event
+		var key string
 		var ok bool
-		if event, ok = obj.(*Event); !ok {
+		if key, ok = obj.(string); !ok {
 			c.workqueue.Forget(obj)
 			c.errorHandler.Handle(errors.NewWithDetails("expected string in workqueue", "value", obj))
 			return nil
 		}
 
-		if err := c.processItem(event); err != nil {
+		itemCtx := ctx
+		if c.timeout > 0 {
+			var cancel context.CancelFunc
+			itemCtx, cancel = context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+		}
+
+		if err := c.processItem(itemCtx, key); err != nil {
+			c.workqueue.setError(key, err)
+			if c.maxRetries > 0 && c.workqueue.NumRequeues(key) >= c.maxRetries {
+				c.workqueue.Forget(key)
+				return errors.WrapIfWithDetails(err, "could not sync; dropping after max retries", "key", key, "retries", c.maxRetries)
+			}
 			// Put the item back on the workqueue to handle any transient errors.
-			c.workqueue.AddRateLimited(event)
-			return errors.WrapIfWithDetails(err, "could not sync; requeuing", "key", event.key)
+			c.workqueue.AddRateLimited(key)
+			return errors.WrapIfWithDetails(err, "could not sync; requeuing", "key", key)
 		}
 
 		c.workqueue.Forget(obj)
+		c.workqueue.setError(obj, nil)
 
 		return nil
 	}(obj)
@@ -178,88 +342,234 @@ func (c *Controller) processNextWorkItem() bool {
 	return true
 }
 
-func (c *Controller) processItem(event *Event) error {
-	var labelsToSet map[string]string
-	var err error
+func (c *Controller) processItem(ctx context.Context, key string) error {
+	resourceType, objectKey, err := splitKey(key)
+	if err != nil {
+		return err
+	}
 
-	namespace, name, err := cache.SplitMetaNamespaceKey(event.key)
+	switch resourceType {
+	case NPLSResourceType:
+		return c.reconcileNodepool(ctx, objectKey)
+	case NodeResourceType:
+		return c.reconcileNode(ctx, objectKey)
+	}
+
+	return errors.NewWithDetails("unknown resource type", "key", key)
+}
+
+// reconcileNodepool puts the nodes of a node pool on the workqueue, or applies
+// the labels to the next batch of nodes if the NPLS has a rollout strategy
+func (c *Controller) reconcileNodepool(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
-		return errors.WrapIfWithDetails(err, "could not split key", "key", event.key)
+		return errors.WrapIfWithDetails(err, "could not split key", "key", key)
 	}
 
-	if namespace != "" && namespace != c.namespace {
+	if namespace != c.namespace {
 		return nil
 	}
 
-	switch event.resourceType {
-	case NPLSResourceType:
-		var npls *v1alpha1.NodePoolLabelSet
-		if event.eventType == AddEvent || event.eventType == UpdateEvent {
-			npls, err = c.nplsInformer.Lister().NodePoolLabelSets(namespace).Get(name)
-			if err != nil {
-				return errors.WrapIfWithDetails(err, "could not get npls from store", "key", event.key)
-			}
-			labelsToSet = npls.Spec.Labels
-		}
+	npls, err := c.nplsInformer.Lister().NodePoolLabelSets(namespace).Get(name)
+	if k8serrors.IsNotFound(err) {
+		npls, err = nil, nil
+	}
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not get npls from store", "key", key)
+	}
 
-		nodes, err := c.getNodesOfANodepool(name)
-		if err != nil {
-			return errors.WrapIfWithDetails(err, "could not get nodes for a nodepool", "nodepoolName", name)
+	nodes, err := c.getNodesOfANodepool(name)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not get nodes for a nodepool", "nodepoolName", name)
+	}
+	if npls != nil && npls.Spec.Paused {
+		return c.skipNodepool(ctx, npls, nodes)
+	}
+
+	for _, node := range nodes {
+		c.workqueue.Add(nodeKey(node.Name))
+	}
+
+	if npls == nil || npls.Spec.Rollout == nil {
+		c.forgetRolloutBatch(key)
+	}
+	if npls == nil {
+		return nil
+	}
+
+	invalidLabels := c.reportInvalidLabels(npls)
+
+	activeNodes := make([]api_v1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if !isNodePaused(&node) {
+			activeNodes = append(activeNodes, node)
 		}
-		for _, node := range nodes {
-			err := c.labeler.SyncLabels(&node, labelsToSet)
-			if err != nil {
-				c.errorHandler.Handle(err)
+	}
+
+	if npls.Spec.Rollout != nil {
+		return c.rolloutLabels(ctx, key, npls, activeNodes, invalidLabels)
+	}
+
+	status := npls.Status.DeepCopy()
+	status.Message = invalidLabels
+
+	// the state is only tracked from lifting a pause until the nodes are synced
+	if status.State == v1alpha1.NodePoolLabelSetStatePaused || status.State == v1alpha1.NodePoolLabelSetStateSyncing {
+		status.State = v1alpha1.NodePoolLabelSetStateSynced
+		for i := range activeNodes {
+			if !c.isNodeUpToDate(&activeNodes[i], nplsOwner(npls), npls.Spec.Labels) {
+				status.State = v1alpha1.NodePoolLabelSetStateSyncing
+				c.workqueue.AddAfter(nplsKey(key), syncStateRecheckDelay)
+				break
 			}
 		}
-	case NodeResourceType:
-		node, err := c.nodeInformer.Lister().Get(name)
-		if err != nil {
-			return errors.WrapIfWithDetails(err, "could not get node from store", "node", name)
-		}
-		npls, err := c.getRelatedNPLSForNode(node)
-		if k8serrors.IsNotFound(errors.Cause(err)) {
-			err = nil
-			npls = nil
-		}
-		if err != nil {
-			return errors.WrapIfWithDetails(err, "could not get related npls for a node", "node", name)
-		}
-		if npls != nil {
-			labelsToSet = npls.Spec.Labels
-		}
-		err = c.labeler.SyncLabels(node, labelsToSet)
-		if err != nil {
-			c.errorHandler.Handle(err)
-		}
 	}
+
+	return c.updateStatus(ctx, npls, status)
+}
+
+// reportInvalidLabels records a warning event about the labels of an NPLS which
+// are not applied because they are invalid and gives back the status message
+func (c *Controller) reportInvalidLabels(npls *v1alpha1.NodePoolLabelSet) string {
+	invalid := c.labeler.InvalidLabels(npls.Spec.Labels)
+	if len(invalid) == 0 {
+		return ""
+	}
+
+	reasons := make([]string, 0, len(invalid))
+	for label, reason := range invalid {
+		reasons = append(reasons, fmt.Sprintf("%s (%s)", label, reason))
+	}
+	sort.Strings(reasons)
+
+	message := "invalid labels are not applied: " + strings.Join(reasons, "; ")
+	c.recorder.Event(npls, api_v1.EventTypeWarning, invalidLabelsReason, message)
+
+	return message
+}
+
+// reconcileNode applies the labels of its node pool to a node, unless the node
+// waits for its batch of a rollout
+func (c *Controller) reconcileNode(ctx context.Context, name string) error {
+	cachedNode, err := c.nodeInformer.Lister().Get(name)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not get node from store", "node", name)
+	}
+	node := cachedNode.DeepCopy()
+
+	var labelsToSet map[string]string
+
+	npls, err := c.getRelatedNPLSForNode(node)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not get related npls for a node", "node", name)
+	}
+	owner := c.nodepoolOwner(c.determineNodepoolNameFromNode(node), npls)
+	if npls != nil {
+		labelsToSet = npls.Spec.Labels
+	}
+
+	if isNodePaused(node) || (npls != nil && npls.Spec.Paused) {
+		c.skipNode(node, owner, labelsToSet)
+		// the startup taint is still removed when its timeout is over
+		c.handleStartupTaint(ctx, node, errReconciliationPaused)
+		return nil
+	}
+
+	if npls != nil && npls.Spec.Rollout != nil && !c.isJoiningNode(node, npls) {
+		// labeled by the rollout of the node pool
+		return nil
+	}
+
+	err = c.syncNode(ctx, node, owner, labelsToSet)
+	c.handleStartupTaint(ctx, node, err)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not sync node labels", "node", name)
+	}
+
 	return nil
 }
 
+// MutateNode sets the labels of the related nodepool on a node object which is
+// about to be created, the controller still reconciles the node afterwards
+func (c *Controller) MutateNode(node *api_v1.Node) error {
+	if !c.nplsInformer.Informer().HasSynced() {
+		return errors.New("npls informer cache is not synced yet")
+	}
+
+	nodepoolName := c.determineNodepoolNameFromNode(node)
+	if nodepoolName == "" {
+		return nil
+	}
+
+	npls, err := c.nplsInformer.Lister().NodePoolLabelSets(c.namespace).Get(nodepoolName)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not get npls from store", "name", nodepoolName)
+	}
+
+	if isNodePaused(node) || npls.Spec.Paused {
+		return nil
+	}
+
+	return c.labeler.ApplyLabels(node, nplsOwner(npls), npls.Spec.Labels)
+}
+
+// nplsOwner gives back the owner of the labels set by an NPLS
+func nplsOwner(npls *v1alpha1.NodePoolLabelSet) labeler.Owner {
+	if npls == nil {
+		return labeler.Owner{}
+	}
+
+	return labeler.Owner{Namespace: npls.Namespace, Name: npls.Name, Generation: npls.Generation}
+}
+
+// nodepoolOwner gives back the owner of the labels of a node pool, the NPLS
+// if it exists, otherwise the node pool itself, so the labels of a deleted
+// NPLS are removed from its nodes
+func (c *Controller) nodepoolOwner(nodepoolName string, npls *v1alpha1.NodePoolLabelSet) labeler.Owner {
+	if npls != nil {
+		return nplsOwner(npls)
+	}
+	if nodepoolName == "" {
+		return labeler.Owner{}
+	}
+
+	return labeler.Owner{Namespace: c.namespace, Name: nodepoolName}
+}
+
 func (c *Controller) getRelatedNPLSForNode(node *api_v1.Node) (*v1alpha1.NodePoolLabelSet, error) {
 	nodepoolName := c.determineNodepoolNameFromNode(node)
 	if nodepoolName == "" {
 		return nil, nil
 	}
 
-	npls, err := c.nplsClientset.LabelsV1alpha1().NodePoolLabelSets(c.namespace).Get(nodepoolName, meta_v1.GetOptions{})
+	npls, err := c.nplsInformer.Lister().NodePoolLabelSets(c.namespace).Get(nodepoolName)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, errors.WrapIfWithDetails(err, "could not get npls", "name", nodepoolName)
+		return nil, errors.WrapIfWithDetails(err, "could not get npls from store", "name", nodepoolName)
 	}
 
 	return npls, nil
 }
 
+// getNodesOfANodepool gives back copies of the cached nodes of a node pool
 func (c *Controller) getNodesOfANodepool(name string) ([]api_v1.Node, error) {
-	nodes, err := c.clientset.CoreV1().Nodes().List(context.TODO(), meta_v1.ListOptions{})
+	nodes, err := c.nodeInformer.Lister().List(labels.Everything())
 	if err != nil {
-		return nil, errors.WrapIf(err, "could not list nodes")
+		return nil, errors.WrapIf(err, "could not list nodes from store")
 	}
 
 	_nodes := make([]api_v1.Node, 0)
-	for _, node := range nodes.Items {
-		if c.determineNodepoolNameFromNode(&node) == name {
-			_nodes = append(_nodes, node)
+	for _, node := range nodes {
+		if c.determineNodepoolNameFromNode(node) == name {
+			_nodes = append(_nodes, *node.DeepCopy())
 		}
 	}
 
@@ -267,13 +577,8 @@ func (c *Controller) getNodesOfANodepool(name string) ([]api_v1.Node, error) {
 }
 
 func (c *Controller) determineNodepoolNameFromNode(node *api_v1.Node) string {
-	labels := node.GetLabels()
-
-	for _, label := range c.nodepoolNameLabels {
-		if labels[label] != "" {
-			return labels[label]
-		}
-	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	return ""
+	return npls.NodepoolName(node, c.nodepoolNameLabels)
 }
@@ -0,0 +1,178 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	typed_corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
+)
+
+// blockingClient is a fake client whose node patches block until the context
+// of the patch is done or release is closed, the names of the patched nodes
+// are sent on patches
+type blockingClient struct {
+	*k8sfake.Clientset
+
+	patches chan string
+	release chan struct{}
+}
+
+func (c *blockingClient) CoreV1() typed_corev1.CoreV1Interface {
+	return &blockingCoreV1{CoreV1Interface: c.Clientset.CoreV1(), client: c}
+}
+
+type blockingCoreV1 struct {
+	typed_corev1.CoreV1Interface
+
+	client *blockingClient
+}
+
+func (c *blockingCoreV1) Nodes() typed_corev1.NodeInterface {
+	return &blockingNodes{NodeInterface: c.CoreV1Interface.Nodes(), client: c.client}
+}
+
+type blockingNodes struct {
+	typed_corev1.NodeInterface
+
+	client *blockingClient
+}
+
+func (n *blockingNodes) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts meta_v1.PatchOptions, subresources ...string) (*api_v1.Node, error) {
+	n.client.patches <- name
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-n.client.release:
+	}
+
+	return n.NodeInterface.Patch(ctx, name, pt, data, opts, subresources...)
+}
+
+func TestShutdown(t *testing.T) {
+	const gracePeriod = 500 * time.Millisecond
+
+	tests := []struct {
+		name string
+		// finishes tells whether the in-flight item finishes right after the shutdown starts
+		finishes bool
+	}{
+		{
+			name: "in-flight item is cancelled when the grace period is over",
+		},
+		{
+			name:     "shutdown does not wait for the grace period once the in-flight item finishes",
+			finishes: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			set := &v1alpha1.NodePoolLabelSet{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "pool", Namespace: testNamespace},
+				Spec:       v1alpha1.NodePoolLabelSetSpec{Labels: map[string]string{"env": "prod"}},
+			}
+			c := newTestController(t, []*api_v1.Node{
+				testNode(t, "node-a", "pool", nil, nil, nil),
+				testNode(t, "node-b", "pool", nil, nil, nil),
+				testNode(t, "node-c", "pool", nil, nil, nil),
+			}, set)
+			client := &blockingClient{
+				Clientset: c.k8sClient,
+				patches:   make(chan string, 3),
+				release:   make(chan struct{}),
+			}
+			c.labeler = labeler.New(labeler.Config{}, client, c.logger, c.errorHandler)
+			// informers started on empty stores enqueue the existing nodes
+			c.nodeInformerFactory, c.nodeInformer = GetNodeInformer(c.k8sClient, 0, c.workqueue, c.logger)
+			c.nplsInformerFactory, c.nplsInformer = GetNPLSInformer(c.nplsClient, 0, c.workqueue, c.logger)
+			c.workers = 1
+			c.shutdownGracePeriod = gracePeriod
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			stopped := make(chan error, 1)
+			go func() {
+				stopped <- c.Start(ctx)
+			}()
+
+			var patched string
+			select {
+			case patched = <-client.patches:
+			case <-time.After(wait.ForeverTestTimeout):
+				t.Fatal("no node was patched")
+			}
+			// the other nodes are queued behind the blocked one
+			err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+				return c.workqueue.Len() >= 2, nil
+			})
+			if err != nil {
+				t.Fatalf("expected queued nodes, got %d items", c.workqueue.Len())
+			}
+
+			start := time.Now()
+			cancel()
+			if test.finishes {
+				close(client.release)
+			}
+
+			select {
+			case err := <-stopped:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			case <-time.After(wait.ForeverTestTimeout):
+				t.Fatal("controller did not stop")
+			}
+			elapsed := time.Since(start)
+
+			if test.finishes && elapsed >= gracePeriod {
+				t.Fatalf("expected shutdown before the grace period, took %s", elapsed)
+			}
+			if !test.finishes && elapsed < gracePeriod {
+				t.Fatalf("expected in-flight item to run for the grace period, cancelled after %s", elapsed)
+			}
+
+			node, err := c.k8sClient.CoreV1().Nodes().Get(context.Background(), patched, meta_v1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if labeled := node.Labels["env"] == "prod"; labeled != test.finishes {
+				t.Fatalf("expected in-flight patch of %s to be applied: %t, labels: %v", patched, test.finishes, node.Labels)
+			}
+
+			if !c.workqueue.ShuttingDown() {
+				t.Fatal("expected workqueue to be shut down")
+			}
+			select {
+			case name := <-client.patches:
+				t.Fatalf("expected queued items to be dropped, %s was patched", name)
+			default:
+			}
+		})
+	}
+}
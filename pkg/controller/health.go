@@ -0,0 +1,110 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+)
+
+const (
+	defaultStuckWorkerThreshold = 5 * time.Minute
+
+	// apiSuccessWindow is how long a successful API call keeps the controller ready
+	apiSuccessWindow = 30 * time.Second
+)
+
+// Live returns an error if there are pending items in the workqueue but the
+// workers neither picked up nor finished an item for a while. A worker waiting
+// for items takes the pending ones, no matter how long the queue was idle.
+func (c *Controller) Live() error {
+	if atomic.LoadInt32(&c.running) == 0 {
+		return nil
+	}
+
+	pending := c.workqueue.Len()
+	if pending == 0 || atomic.LoadInt32(&c.idleWorkers) > 0 {
+		return nil
+	}
+
+	lastProgress := time.Unix(0, atomic.LoadInt64(&c.lastProgress))
+	if since := time.Since(lastProgress); since > c.stuckWorkerThreshold {
+		return errors.NewWithDetails("workers are stuck", "pending", pending, "lastProgress", since.String())
+	}
+
+	return nil
+}
+
+// Ready returns an error until the informer caches are synced and the workers
+// are running, or if the k8s API server is not reachable. The operator runs
+// without leader election, so there is no leader status to report; every
+// replica also serves the admission webhooks through the same readiness.
+func (c *Controller) Ready() error {
+	if !c.nodeInformer.Informer().HasSynced() || !c.nplsInformer.Informer().HasSynced() {
+		return errors.New("informer caches are not synced")
+	}
+
+	if atomic.LoadInt32(&c.running) == 0 {
+		return errors.New("workers are not running")
+	}
+
+	lastAPISuccess := time.Unix(0, atomic.LoadInt64(c.lastAPISuccess))
+	if time.Since(lastAPISuccess) < apiSuccessWindow {
+		return nil
+	}
+
+	_, err := c.clientset.Discovery().ServerVersion()
+	if err != nil {
+		return errors.WrapIf(err, "k8s API server is not reachable")
+	}
+
+	return nil
+}
+
+func (c *Controller) recordProgress() {
+	atomic.StoreInt64(&c.lastProgress, time.Now().UnixNano())
+}
+
+// withAPISuccessRecorder gives back a copy of a k8s config whose clients store
+// the time of the last request answered by the API server in lastSuccess
+func withAPISuccessRecorder(config *rest.Config, lastSuccess *int64) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.WrapTransport = transport.Wrappers(config.WrapTransport, func(rt http.RoundTripper) http.RoundTripper {
+		return &apiSuccessRecorder{next: rt, lastSuccess: lastSuccess}
+	})
+
+	return config
+}
+
+// apiSuccessRecorder records the responses of the API server, server errors
+// and failed requests are not considered successful
+type apiSuccessRecorder struct {
+	next        http.RoundTripper
+	lastSuccess *int64
+}
+
+func (r *apiSuccessRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err == nil && resp.StatusCode < http.StatusInternalServerError {
+		atomic.StoreInt64(r.lastSuccess, time.Now().UnixNano())
+	}
+
+	return resp, err
+}
@@ -0,0 +1,124 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestLive(t *testing.T) {
+	tests := []struct {
+		name        string
+		stopped     bool
+		queued      []string
+		idleWorkers int32
+		// lastProgress is how long ago the workers made progress
+		lastProgress time.Duration
+		live         bool
+	}{
+		{
+			name:         "stopped workers",
+			stopped:      true,
+			queued:       []string{nodeKey("node-a")},
+			lastProgress: time.Hour,
+			live:         true,
+		},
+		{
+			name:         "empty queue after a long idle period",
+			lastProgress: time.Hour,
+			live:         true,
+		},
+		{
+			name:         "item queued after a long idle period is waited for by a worker",
+			queued:       []string{nodeKey("node-a")},
+			idleWorkers:  1,
+			lastProgress: time.Hour,
+			live:         true,
+		},
+		{
+			name:         "busy workers made progress recently",
+			queued:       []string{nodeKey("node-a")},
+			lastProgress: time.Second,
+			live:         true,
+		},
+		{
+			name:         "busy workers made no progress for a while",
+			queued:       []string{nodeKey("node-a")},
+			lastProgress: time.Hour,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			c := newTestController(t, nil, nil)
+			c.stuckWorkerThreshold = time.Minute
+			if !test.stopped {
+				c.running = 1
+			}
+			c.idleWorkers = test.idleWorkers
+			c.lastProgress = time.Now().Add(-test.lastProgress).UnixNano()
+			for _, key := range test.queued {
+				c.workqueue.Add(key)
+			}
+
+			err := c.Live()
+			if test.live && err != nil {
+				t.Fatalf("expected to be live, got %v", err)
+			}
+			if !test.live && err == nil {
+				t.Fatal("expected workers to be stuck")
+			}
+		})
+	}
+}
+
+func TestLiveWhenTheQueueIsFilledAfterALongIdlePeriod(t *testing.T) {
+	c := newTestController(t, nil, nil)
+	c.stuckWorkerThreshold = time.Minute
+	c.running = 1
+
+	processed := make(chan bool)
+	go func() {
+		processed <- c.processNextWorkItem(context.Background(), context.Background())
+	}()
+	err := wait.PollImmediate(time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		return atomic.LoadInt32(&c.idleWorkers) == 1, nil
+	})
+	if err != nil {
+		t.Fatal("worker is not waiting for items")
+	}
+	atomic.StoreInt64(&c.lastProgress, time.Now().Add(-time.Hour).UnixNano())
+
+	// live whether or not the worker already picked up the item
+	c.workqueue.Add(nodeKey("missing"))
+	if err := c.Live(); err != nil {
+		t.Fatalf("expected to be live, got %v", err)
+	}
+
+	select {
+	case <-processed:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("item was not processed")
+	}
+	if since := time.Since(time.Unix(0, atomic.LoadInt64(&c.lastProgress))); since > time.Minute {
+		t.Fatalf("expected progress to be recorded, last progress %s ago", since)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sort"
+
+	"emperror.dev/errors"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
+)
+
+// NodeInfo is the operator's view of a node
+type NodeInfo struct {
+	Name          string                   `json:"name"`
+	Nodepool      string                   `json:"nodepool"`
+	DesiredLabels map[string]string        `json:"desiredLabels"`
+	ActualLabels  map[string]string        `json:"actualLabels"`
+	ManagedLabels []string                 `json:"managedLabels"`
+	LabelOwners   map[string]labeler.Owner `json:"labelOwners"`
+	UpToDate      bool                     `json:"upToDate"`
+	Paused        bool                     `json:"paused"`
+}
+
+// NodepoolInfo is the operator's view of a node pool
+type NodepoolInfo struct {
+	Name   string            `json:"name"`
+	HasSet bool              `json:"hasLabelSet"`
+	Paused bool              `json:"paused"`
+	Labels map[string]string `json:"labels"`
+	Nodes  []NodeInfo        `json:"nodes"`
+}
+
+// QueueInfo is the content of the workqueue
+type QueueInfo struct {
+	Items  []string            `json:"items"`
+	Errors map[string]KeyError `json:"errors"`
+}
+
+// Nodepools gives back the node pools detected from the node labels and the
+// NPLS resources along with their member nodes, based on the informer caches
+func (c *Controller) Nodepools() ([]NodepoolInfo, error) {
+	nodes, err := c.nodeInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not list nodes from store")
+	}
+
+	nplss, err := c.nplsInformer.Lister().NodePoolLabelSets(c.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not list npls from store")
+	}
+
+	pools := make(map[string]*NodepoolInfo)
+	nplsByName := make(map[string]*v1alpha1.NodePoolLabelSet, len(nplss))
+	for _, npls := range nplss {
+		nplsByName[npls.Name] = npls
+		pools[npls.Name] = &NodepoolInfo{
+			Name:   npls.Name,
+			HasSet: true,
+			Paused: npls.Spec.Paused,
+			Labels: npls.Spec.Labels,
+			Nodes:  make([]NodeInfo, 0),
+		}
+	}
+
+	for _, node := range nodes {
+		nodepoolName := c.determineNodepoolNameFromNode(node)
+		if nodepoolName == "" {
+			continue
+		}
+		pool, ok := pools[nodepoolName]
+		if !ok {
+			pool = &NodepoolInfo{
+				Name:  nodepoolName,
+				Nodes: make([]NodeInfo, 0),
+			}
+			pools[nodepoolName] = pool
+		}
+		owner := c.nodepoolOwner(nodepoolName, nplsByName[nodepoolName])
+		pool.Nodes = append(pool.Nodes, c.nodeInfo(node, nodepoolName, owner, pool.Labels))
+	}
+
+	infos := make([]NodepoolInfo, 0, len(pools))
+	for _, pool := range pools {
+		sort.Slice(pool.Nodes, func(i, j int) bool {
+			return pool.Nodes[i].Name < pool.Nodes[j].Name
+		})
+		infos = append(infos, *pool)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos, nil
+}
+
+// Node gives back the operator's view of a single node
+func (c *Controller) Node(name string) (*NodeInfo, error) {
+	node, err := c.nodeInformer.Lister().Get(name)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not get node from store", "node", name)
+	}
+
+	nodepoolName := c.determineNodepoolNameFromNode(node)
+
+	npls, err := c.getRelatedNPLSForNode(node)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not get related npls for a node", "node", name)
+	}
+	var labelsToSet map[string]string
+	if npls != nil {
+		labelsToSet = npls.Spec.Labels
+	}
+
+	info := c.nodeInfo(node, nodepoolName, c.nodepoolOwner(nodepoolName, npls), labelsToSet)
+
+	return &info, nil
+}
+
+// Queue gives back the items waiting in the workqueue and the last error of
+// the failed items
+func (c *Controller) Queue() QueueInfo {
+	return QueueInfo{
+		Items:  c.workqueue.items(),
+		Errors: c.workqueue.lastErrors(),
+	}
+}
+
+// PatchStats gives back the number of applied node patches and the ones
+// skipped because the node was up to date
+func (c *Controller) PatchStats() labeler.PatchStats {
+	return c.labeler.PatchStats()
+}
+
+func (c *Controller) nodeInfo(node *api_v1.Node, nodepoolName string, owner labeler.Owner, labelsToSet map[string]string) NodeInfo {
+	return NodeInfo{
+		Name:          node.Name,
+		Nodepool:      nodepoolName,
+		DesiredLabels: c.labeler.AllowedLabels(labelsToSet),
+		ActualLabels:  node.GetLabels(),
+		ManagedLabels: c.labeler.ManagedLabels(node),
+		LabelOwners:   c.labeler.LabelOwners(node),
+		UpToDate:      c.isNodeUpToDate(node, owner, labelsToSet),
+		Paused:        isNodePaused(node),
+	}
+}
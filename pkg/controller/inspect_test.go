@@ -0,0 +1,64 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	"emperror.dev/emperror"
+	api_v1 "k8s.io/api/core/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
+)
+
+func TestInspectNodepoolWithoutSet(t *testing.T) {
+	logger := log.NewLogger(log.Config{Format: "logfmt", Level: "error"})
+	l := labeler.New(labeler.Config{}, k8sfake.NewSimpleClientset(), logger, emperror.NewNoopHandler())
+	// the nodes were labeled by a set which was deleted since
+	deleted := &labeler.Owner{Namespace: testNamespace, Name: "pool", Generation: 1}
+
+	c := newTestController(t, []*api_v1.Node{
+		testNode(t, "node-a", "pool", l, deleted, map[string]string{"env": "dev"}),
+		testNode(t, "node-b", "pool", l, deleted, map[string]string{"env": "dev"}),
+	}, nil)
+	c.reconcile(t, "pool")
+
+	if got := c.labeledNodes(t, "env", "dev"); got != 0 {
+		t.Fatalf("expected the labels of the deleted set to be removed, got %d labeled nodes", got)
+	}
+
+	node, err := c.Node("node-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !node.UpToDate {
+		t.Errorf("expected node %s to be up to date", node.Name)
+	}
+
+	pools, err := c.Nodepools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools) != 1 || pools[0].HasSet || len(pools[0].Nodes) != 2 {
+		t.Fatalf("expected a single node pool without a set with 2 nodes, got %+v", pools)
+	}
+	for _, node := range pools[0].Nodes {
+		if !node.UpToDate {
+			t.Errorf("expected node %s to be up to date", node.Name)
+		}
+	}
+}
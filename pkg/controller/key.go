@@ -0,0 +1,45 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+// Workqueue keys are built from the resource type and the key of the object,
+// so the events of the same object coalesce in the workqueue and are
+// reconciled once based on the current state of the object
+
+// nodeKey gives back the workqueue key of a node
+func nodeKey(name string) string {
+	return NodeResourceType + "/" + name
+}
+
+// nplsKey gives back the workqueue key of an NPLS from its namespace/name key
+func nplsKey(key string) string {
+	return NPLSResourceType + "/" + key
+}
+
+// splitKey gives back the resource type and the object key of a workqueue key
+func splitKey(key string) (string, string, error) {
+	parts := strings.SplitN(key, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", errors.NewWithDetails("invalid workqueue key", "key", key)
+	}
+
+	return parts[0], parts[1], nil
+}
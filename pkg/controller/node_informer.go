@@ -17,11 +17,15 @@ package controller
 import (
 	"time"
 
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/informers"
 	corev1 "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
+
+	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
 )
 
 const (
@@ -29,7 +33,7 @@ const (
 )
 
 // GetNodeInformer creates and gives back a shared Node informer and its factory
-func GetNodeInformer(clientset kubernetes.Interface, resync time.Duration, queue workqueue.RateLimitingInterface) (informers.SharedInformerFactory, corev1.NodeInformer) {
+func GetNodeInformer(clientset kubernetes.Interface, resync time.Duration, queue workqueue.RateLimitingInterface, logger log.Logger) (informers.SharedInformerFactory, corev1.NodeInformer) {
 	factory := informers.NewSharedInformerFactory(clientset, resync)
 	nodeInformer := factory.Core().V1().Nodes()
 
@@ -37,7 +41,23 @@ func GetNodeInformer(clientset kubernetes.Interface, resync time.Duration, queue
 		AddFunc: func(obj interface{}) {
 			key, err := cache.MetaNamespaceKeyFunc(obj)
 			if err == nil {
-				queue.Add(NewEvent(NodeResourceType, AddEvent, key))
+				logger.WithField("node", key).Debug("node added")
+				queue.Add(nodeKey(key))
+			}
+		},
+		UpdateFunc: func(old, new interface{}) {
+			// only label changes (eg. moving to another node pool) and pausing
+			// need reconciliation, status heartbeats are ignored
+			oldNode, ok := old.(*api_v1.Node)
+			newNode, _ := new.(*api_v1.Node)
+			if ok && newNode != nil && labels.Equals(oldNode.GetLabels(), newNode.GetLabels()) &&
+				oldNode.GetAnnotations()[PausedAnnotation] == newNode.GetAnnotations()[PausedAnnotation] {
+				return
+			}
+			key, err := cache.MetaNamespaceKeyFunc(new)
+			if err == nil {
+				logger.WithField("node", key).Debug("node updated")
+				queue.Add(nodeKey(key))
 			}
 		},
 	})
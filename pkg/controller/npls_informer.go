@@ -20,6 +20,8 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 
+	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
 	clientset "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned"
 	informers "github.com/banzaicloud/nodepool-labels-operator/pkg/client/informers/externalversions"
 	v1alpha "github.com/banzaicloud/nodepool-labels-operator/pkg/client/informers/externalversions/nodepoollabelset/v1alpha1"
@@ -30,27 +32,36 @@ const (
 )
 
 // GetNPLSInformer creates and gives back a shared NPLS informer and its factory
-func GetNPLSInformer(clientset clientset.Interface, resync time.Duration, queue workqueue.RateLimitingInterface) (informers.SharedInformerFactory, v1alpha.NodePoolLabelSetInformer) {
+func GetNPLSInformer(clientset clientset.Interface, resync time.Duration, queue workqueue.RateLimitingInterface, logger log.Logger) (informers.SharedInformerFactory, v1alpha.NodePoolLabelSetInformer) {
 	factory := informers.NewSharedInformerFactory(clientset, resync)
 	informer := factory.Labels().V1alpha1().NodePoolLabelSets()
 
 	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		UpdateFunc: func(old, new interface{}) {
+			// status updates don't change the generation and need no reconciliation
+			oldNPLS, ok := old.(*v1alpha1.NodePoolLabelSet)
+			newNPLS, _ := new.(*v1alpha1.NodePoolLabelSet)
+			if ok && newNPLS != nil && oldNPLS.Generation == newNPLS.Generation {
+				return
+			}
 			key, err := cache.MetaNamespaceKeyFunc(old)
 			if err == nil {
-				queue.Add(NewEvent(NPLSResourceType, UpdateEvent, key))
+				logger.WithField("npls", key).Debug("npls updated")
+				queue.Add(nplsKey(key))
 			}
 		},
 		AddFunc: func(obj interface{}) {
 			key, err := cache.MetaNamespaceKeyFunc(obj)
 			if err == nil {
-				queue.Add(NewEvent(NPLSResourceType, AddEvent, key))
+				logger.WithField("npls", key).Debug("npls added")
+				queue.Add(nplsKey(key))
 			}
 		},
 		DeleteFunc: func(obj interface{}) {
-			key, err := cache.MetaNamespaceKeyFunc(obj)
+			key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 			if err == nil {
-				queue.Add(NewEvent(NPLSResourceType, DeleteEvent, key))
+				logger.WithField("npls", key).Debug("npls deleted")
+				queue.Add(nplsKey(key))
 			}
 		},
 	})
@@ -0,0 +1,86 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"emperror.dev/errors"
+	api_v1 "k8s.io/api/core/v1"
+
+	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
+)
+
+const (
+	// PausedAnnotation stops the reconciliation of a node when set to true
+	PausedAnnotation = "nodepool.banzaicloud.io/paused"
+
+	reconciliationPausedReason = "ReconciliationPaused"
+)
+
+var errReconciliationPaused = errors.New("reconciliation is paused")
+
+func isNodePaused(node *api_v1.Node) bool {
+	paused, _ := strconv.ParseBool(node.GetAnnotations()[PausedAnnotation])
+
+	return paused
+}
+
+// skipNode records that a paused node was not reconciled along with its drift
+// from the desired labels
+func (c *Controller) skipNode(node *api_v1.Node, owner labeler.Owner, labelsToSet map[string]string) {
+	drifted := !c.isNodeUpToDate(node, owner, labelsToSet)
+
+	c.logger.WithFields(log.Fields{
+		"node":    node.Name,
+		"drifted": drifted,
+	}).Info("reconciliation is paused, skipping node")
+
+	message := "reconciliation is paused, labels are in sync"
+	if drifted {
+		message = "reconciliation is paused, labels drifted from the desired state"
+	}
+	c.recorder.Event(node, api_v1.EventTypeNormal, reconciliationPausedReason, message)
+}
+
+// skipNodepool records that a paused pool was not reconciled along with the
+// number of drifted nodes
+func (c *Controller) skipNodepool(ctx context.Context, npls *v1alpha1.NodePoolLabelSet, nodes []api_v1.Node) error {
+	drifted := 0
+	for i := range nodes {
+		if !c.isNodeUpToDate(&nodes[i], nplsOwner(npls), npls.Spec.Labels) {
+			drifted++
+		}
+	}
+
+	c.logger.WithFields(log.Fields{
+		"npls":    npls.Name,
+		"nodes":   len(nodes),
+		"drifted": drifted,
+	}).Info("reconciliation is paused, skipping nodepool")
+
+	message := fmt.Sprintf("reconciliation is paused, %d of %d nodes drifted from the desired state", drifted, len(nodes))
+	c.recorder.Event(npls, api_v1.EventTypeNormal, reconciliationPausedReason, message)
+
+	status := npls.Status.DeepCopy()
+	status.State = v1alpha1.NodePoolLabelSetStatePaused
+	status.Message = message
+
+	return c.updateStatus(ctx, npls, status)
+}
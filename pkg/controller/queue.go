@@ -0,0 +1,125 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// KeyError is the last error of a workqueue item
+type KeyError struct {
+	Error string    `json:"error"`
+	Time  time.Time `json:"time"`
+}
+
+// inspectableQueue is a rate limiting workqueue which keeps track of the items
+// waiting to be processed and the last error of each item, like the workqueue
+// itself it holds each item once no matter how many times it was added
+type inspectableQueue struct {
+	workqueue.RateLimitingInterface
+
+	mu      sync.Mutex
+	pending map[string]bool
+	errors  map[string]KeyError
+}
+
+func newInspectableQueue(rateLimiter workqueue.RateLimiter) *inspectableQueue {
+	return &inspectableQueue{
+		RateLimitingInterface: workqueue.NewRateLimitingQueue(rateLimiter),
+
+		pending: make(map[string]bool),
+		errors:  make(map[string]KeyError),
+	}
+}
+
+func (q *inspectableQueue) Add(item interface{}) {
+	q.markPending(item)
+	q.RateLimitingInterface.Add(item)
+}
+
+func (q *inspectableQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.markPending(item)
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+func (q *inspectableQueue) AddRateLimited(item interface{}) {
+	q.markPending(item)
+	q.RateLimitingInterface.AddRateLimited(item)
+}
+
+func (q *inspectableQueue) Get() (interface{}, bool) {
+	item, shutdown := q.RateLimitingInterface.Get()
+	if !shutdown {
+		q.mu.Lock()
+		delete(q.pending, fmt.Sprint(item))
+		q.mu.Unlock()
+	}
+
+	return item, shutdown
+}
+
+func (q *inspectableQueue) markPending(item interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending[fmt.Sprint(item)] = true
+}
+
+func (q *inspectableQueue) setError(item interface{}, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	key := fmt.Sprint(item)
+	if err == nil {
+		delete(q.errors, key)
+		return
+	}
+	q.errors[key] = KeyError{
+		Error: err.Error(),
+		Time:  time.Now(),
+	}
+}
+
+// items gives back the sorted list of items waiting to be processed, including
+// the ones waiting for a retry
+func (q *inspectableQueue) items() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := make([]string, 0, len(q.pending))
+	for item := range q.pending {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+
+	return items
+}
+
+func (q *inspectableQueue) lastErrors() map[string]KeyError {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	errs := make(map[string]KeyError, len(q.errors))
+	for key, err := range q.errors {
+		errs[key] = err
+	}
+
+	return errs
+}
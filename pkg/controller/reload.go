@@ -0,0 +1,43 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SetNodepoolNameLabels changes the labels used to determine the node pool of
+// a node, EnqueueAllNodes should be called afterwards to apply the change
+func (c *Controller) SetNodepoolNameLabels(nodepoolNameLabels []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nodepoolNameLabels = nodepoolNameLabels
+}
+
+// EnqueueAllNodes puts every node on the workqueue to be reconciled
+func (c *Controller) EnqueueAllNodes() error {
+	nodes, err := c.nodeInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return errors.WrapIf(err, "could not list nodes from store")
+	}
+
+	for _, node := range nodes {
+		c.workqueue.Add(nodeKey(node.Name))
+	}
+
+	return nil
+}
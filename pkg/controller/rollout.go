@@ -0,0 +1,255 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+)
+
+const (
+	// rolloutBatchRecheckDelay is how often the informer cache is checked
+	// whether it shows the nodes of the last batch of a rollout updated
+	rolloutBatchRecheckDelay = time.Second
+	// rolloutBatchSyncTimeout is how long the informer cache is waited for,
+	// the nodes of the last batch still outdated after it are updated again
+	rolloutBatchSyncTimeout = 30 * time.Second
+)
+
+// rolloutBatch is the last batch of nodes updated by the rollout of an NPLS,
+// the next batch is only started once the informer cache shows its nodes updated
+type rolloutBatch struct {
+	generation int64
+	nodes      map[string]bool
+	time       time.Time
+}
+
+// rolloutLabels applies the labels of an NPLS to the next batch of outdated
+// nodes according to its rollout strategy and records the progress in the status
+func (c *Controller) rolloutLabels(ctx context.Context, key string, npls *v1alpha1.NodePoolLabelSet, nodes []api_v1.Node, invalidLabels string) error {
+	strategy := npls.Spec.Rollout
+	logger := c.logger.WithField("npls", key)
+
+	lastBatch, hasLastBatch := c.getRolloutBatch(key)
+	if hasLastBatch && (lastBatch.generation != npls.Generation || time.Since(lastBatch.time) > rolloutBatchSyncTimeout) {
+		hasLastBatch = false
+	}
+
+	// nodes of the last batch which are outdated in the informer cache were
+	// updated already, the cache didn't catch up with them yet
+	outdated := make([]api_v1.Node, 0)
+	pending := 0
+	unavailable := 0
+	for _, node := range nodes {
+		if !isNodeReady(&node) {
+			unavailable++
+		}
+		if c.isNodeUpToDate(&node, nplsOwner(npls), npls.Spec.Labels) {
+			continue
+		}
+		if hasLastBatch && lastBatch.nodes[node.Name] {
+			pending++
+			continue
+		}
+		outdated = append(outdated, node)
+	}
+	if pending == 0 {
+		c.forgetRolloutBatch(key)
+	}
+	// unavailable nodes are updated first, they don't reduce the availability further
+	sort.Slice(outdated, func(i, j int) bool {
+		if readyI, readyJ := isNodeReady(&outdated[i]), isNodeReady(&outdated[j]); readyI != readyJ {
+			return readyJ
+		}
+		return outdated[i].Name < outdated[j].Name
+	})
+
+	status := npls.Status.DeepCopy()
+	if status.Rollout == nil || status.Rollout.ObservedGeneration != npls.Generation {
+		status.Rollout = &v1alpha1.RolloutStatus{
+			ObservedGeneration: npls.Generation,
+		}
+	}
+	status.Rollout.TotalNodes = int32(len(nodes))
+	status.Rollout.UpdatedNodes = int32(len(nodes) - len(outdated))
+
+	var pause time.Duration
+	if strategy.PauseBetweenBatches != nil {
+		pause = strategy.PauseBetweenBatches.Duration
+	}
+
+	switch {
+	case len(outdated) == 0 && pending == 0:
+		status.State = v1alpha1.NodePoolLabelSetStateSynced
+		status.Message = ""
+	case strategy.Paused:
+		logger.Info("rollout is paused")
+		status.State = v1alpha1.NodePoolLabelSetStatePaused
+		status.Message = fmt.Sprintf("rollout is paused, %d nodes are outdated", len(outdated))
+	case pending > 0:
+		logger.WithField("pending", pending).Debug("waiting for the informer cache to show the last batch updated")
+		status.State = v1alpha1.NodePoolLabelSetStateSyncing
+		status.Message = fmt.Sprintf("%d nodes are outdated", len(outdated))
+		c.workqueue.AddAfter(nplsKey(key), rolloutBatchRecheckDelay)
+	default:
+		status.State = v1alpha1.NodePoolLabelSetStateSyncing
+		status.Message = fmt.Sprintf("%d nodes are outdated", len(outdated))
+
+		if last := status.Rollout.LastBatchTime; last != nil {
+			if remaining := time.Until(last.Add(pause)); remaining > 0 {
+				c.workqueue.AddAfter(nplsKey(key), remaining)
+				break
+			}
+		}
+
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(
+			intstr.ValueOrDefault(strategy.MaxUnavailable, intstr.FromInt(1)), len(nodes), true)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "invalid maxUnavailable", "npls", key)
+		}
+		if maxUnavailable < 1 {
+			maxUnavailable = 1
+		}
+
+		// the outdated unavailable nodes and as many available ones as the
+		// unavailable nodes of the pool allow
+		batchSize := 0
+		for batchSize < len(outdated) && !isNodeReady(&outdated[batchSize]) {
+			batchSize++
+		}
+		if available := maxUnavailable - unavailable; available > 0 {
+			batchSize += available
+		}
+		if batchSize > len(outdated) {
+			batchSize = len(outdated)
+		}
+		if batchSize == 0 {
+			logger.WithField("unavailable", unavailable).Info("waiting for unavailable nodes")
+			status.Message = fmt.Sprintf("%d nodes are outdated, waiting for %d unavailable nodes", len(outdated), unavailable)
+			c.workqueue.AddAfter(nplsKey(key), syncStateRecheckDelay)
+			break
+		}
+
+		logger.WithField("batchSize", batchSize).Info("updating next batch of nodes")
+		batch := rolloutBatch{
+			generation: npls.Generation,
+			nodes:      make(map[string]bool, batchSize),
+			time:       time.Now(),
+		}
+		for i := range outdated[:batchSize] {
+			node := &outdated[i]
+			err := c.syncNode(ctx, node, nplsOwner(npls), npls.Spec.Labels)
+			if err != nil {
+				c.errorHandler.Handle(err)
+			} else {
+				status.Rollout.UpdatedNodes++
+				batch.nodes[node.Name] = true
+			}
+			c.handleStartupTaint(ctx, node, err)
+		}
+		c.setRolloutBatch(key, batch)
+
+		now := meta_v1.Now()
+		status.Rollout.LastBatchTime = &now
+
+		if status.Rollout.UpdatedNodes < status.Rollout.TotalNodes {
+			status.Message = fmt.Sprintf("%d nodes are outdated", status.Rollout.TotalNodes-status.Rollout.UpdatedNodes)
+			c.workqueue.AddAfter(nplsKey(key), pause)
+		} else {
+			status.State = v1alpha1.NodePoolLabelSetStateSynced
+			status.Message = ""
+		}
+	}
+
+	if invalidLabels != "" {
+		status.Message = strings.TrimPrefix(status.Message+"; "+invalidLabels, "; ")
+	}
+
+	return c.updateStatus(ctx, npls, status)
+}
+
+func (c *Controller) updateStatus(ctx context.Context, npls *v1alpha1.NodePoolLabelSet, status *v1alpha1.NodePoolLabelSetStatus) error {
+	if equality.Semantic.DeepEqual(npls.Status, *status) {
+		return nil
+	}
+
+	// the status is computed from the cached npls, on a conflict it is recomputed when the npls is requeued
+	npls = npls.DeepCopy()
+	npls.Status = *status
+	_, err := c.nplsClientset.LabelsV1alpha1().NodePoolLabelSets(npls.Namespace).UpdateStatus(ctx, npls, meta_v1.UpdateOptions{})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not update npls status", "name", npls.Name)
+	}
+
+	return nil
+}
+
+// isJoiningNode tells whether a node of a node pool with a rollout strategy gets
+// the labels immediately instead of waiting for its batch, which is the case
+// for nodes never labeled by the NPLS: new nodes, nodes moved from another node
+// pool and the nodes of a newly created NPLS. Label changes of nodes labeled by
+// the NPLS before are only applied in batches.
+func (c *Controller) isJoiningNode(node *api_v1.Node, npls *v1alpha1.NodePoolLabelSet) bool {
+	return !c.labeler.IsLabeledBy(node, nplsOwner(npls))
+}
+
+func (c *Controller) getRolloutBatch(key string) (rolloutBatch, bool) {
+	c.rolloutMu.Lock()
+	defer c.rolloutMu.Unlock()
+
+	batch, ok := c.rolloutBatches[key]
+
+	return batch, ok
+}
+
+func (c *Controller) setRolloutBatch(key string, batch rolloutBatch) {
+	c.rolloutMu.Lock()
+	defer c.rolloutMu.Unlock()
+
+	if c.rolloutBatches == nil {
+		c.rolloutBatches = make(map[string]rolloutBatch)
+	}
+	c.rolloutBatches[key] = batch
+}
+
+func (c *Controller) forgetRolloutBatch(key string) {
+	c.rolloutMu.Lock()
+	defer c.rolloutMu.Unlock()
+
+	delete(c.rolloutBatches, key)
+}
+
+// isNodeReady tells whether a node is available, nodes without a ready
+// condition have not finished registering yet
+func isNodeReady(node *api_v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == api_v1.NodeReady {
+			return condition.Status == api_v1.ConditionTrue
+		}
+	}
+
+	return false
+}
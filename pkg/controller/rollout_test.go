@@ -0,0 +1,357 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/emperror"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	nplsfake "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/fake"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/npls"
+)
+
+const testNamespace = "default"
+
+type testController struct {
+	*Controller
+
+	k8sClient  *k8sfake.Clientset
+	nplsClient *nplsfake.Clientset
+}
+
+// newTestController gives back a controller whose informer stores are filled
+// from fake clients by syncStores instead of running informers, set may be nil
+func newTestController(t *testing.T, nodes []*api_v1.Node, set *v1alpha1.NodePoolLabelSet) *testController {
+	t.Helper()
+
+	logger := log.NewLogger(log.Config{Format: "logfmt", Level: "error"})
+	errorHandler := emperror.NewNoopHandler()
+
+	k8sClient := k8sfake.NewSimpleClientset()
+	for _, node := range nodes {
+		if _, err := k8sClient.CoreV1().Nodes().Create(context.Background(), node, meta_v1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sets := make([]runtime.Object, 0, 1)
+	if set != nil {
+		sets = append(sets, set)
+	}
+	nplsClient := nplsfake.NewSimpleClientset(sets...)
+
+	queue := newInspectableQueue(workqueue.DefaultControllerRateLimiter())
+	nodeInformerFactory, nodeInformer := GetNodeInformer(k8sClient, 0, queue, logger)
+	nplsInformerFactory, nplsInformer := GetNPLSInformer(nplsClient, 0, queue, logger)
+
+	c := &testController{
+		Controller: &Controller{
+			namespace:          testNamespace,
+			nodepoolNameLabels: npls.DefaultNodepoolNameLabels,
+			lastAPISuccess:     new(int64),
+
+			labeler: labeler.New(labeler.Config{}, k8sClient, logger, errorHandler),
+
+			nodeInformerFactory: nodeInformerFactory,
+			nodeInformer:        nodeInformer,
+			nplsInformerFactory: nplsInformerFactory,
+			nplsInformer:        nplsInformer,
+
+			workqueue:     queue,
+			clientset:     k8sClient,
+			nplsClientset: nplsClient,
+			recorder:      record.NewFakeRecorder(100),
+
+			logger:       logger,
+			errorHandler: errorHandler,
+		},
+		k8sClient:  k8sClient,
+		nplsClient: nplsClient,
+	}
+	c.syncStores(t)
+
+	return c
+}
+
+// syncStores replaces the content of the informer stores with the objects of the fake clients
+func (c *testController) syncStores(t *testing.T) {
+	t.Helper()
+
+	c.replaceNodes(t, c.nodes(t))
+	c.syncNPLSStore(t)
+}
+
+// nodes gives back the nodes of the fake client
+func (c *testController) nodes(t *testing.T) []api_v1.Node {
+	t.Helper()
+
+	nodes, err := c.k8sClient.CoreV1().Nodes().List(context.Background(), meta_v1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return nodes.Items
+}
+
+// replaceNodes replaces the content of the node informer store
+func (c *testController) replaceNodes(t *testing.T, nodes []api_v1.Node) {
+	t.Helper()
+
+	nodeObjects := make([]interface{}, 0, len(nodes))
+	for i := range nodes {
+		nodeObjects = append(nodeObjects, nodes[i].DeepCopy())
+	}
+	if err := c.nodeInformer.Informer().GetStore().Replace(nodeObjects, ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// syncNPLSStore replaces the content of the npls informer store with the sets of the fake client
+func (c *testController) syncNPLSStore(t *testing.T) {
+	t.Helper()
+
+	sets, err := c.nplsClient.LabelsV1alpha1().NodePoolLabelSets(testNamespace).List(context.Background(), meta_v1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	setObjects := make([]interface{}, 0, len(sets.Items))
+	for i := range sets.Items {
+		setObjects = append(setObjects, &sets.Items[i])
+	}
+	if err := c.nplsInformer.Informer().GetStore().Replace(setObjects, ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// reconcile processes the node keys and then the node pool key the way the
+// workers would after a change of the node pool
+func (c *testController) reconcile(t *testing.T, nodepool string) {
+	t.Helper()
+
+	for _, node := range c.nodes(t) {
+		if err := c.reconcileNode(context.Background(), node.Name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.syncStores(t)
+
+	if err := c.reconcileNodepool(context.Background(), testNamespace+"/"+nodepool); err != nil {
+		t.Fatal(err)
+	}
+	c.syncStores(t)
+}
+
+// labeledNodes gives back the number of nodes with the given label value
+func (c *testController) labeledNodes(t *testing.T, label string, value string) int {
+	t.Helper()
+
+	count := 0
+	for _, node := range c.nodes(t) {
+		if node.Labels[label] == value {
+			count++
+		}
+	}
+
+	return count
+}
+
+func testNode(t *testing.T, name string, nodepool string, l *labeler.Labeler, owner *labeler.Owner, labels map[string]string) *api_v1.Node {
+	t.Helper()
+
+	node := &api_v1.Node{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: meta_v1.Now(),
+			Labels: map[string]string{
+				npls.DefaultNodepoolNameLabels[0]: nodepool,
+			},
+		},
+		Status: api_v1.NodeStatus{
+			Conditions: []api_v1.NodeCondition{{
+				Type:   api_v1.NodeReady,
+				Status: api_v1.ConditionTrue,
+			}},
+		},
+	}
+	if owner != nil {
+		if err := l.ApplyLabels(node, *owner, labels); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return node
+}
+
+func notReady(node *api_v1.Node) *api_v1.Node {
+	node.Status.Conditions[0].Status = api_v1.ConditionFalse
+
+	return node
+}
+
+// testRolloutSet gives back a set with a rollout strategy whose previous
+// generation labeled the nodes with env=old
+func testRolloutSet(maxUnavailable int) *v1alpha1.NodePoolLabelSet {
+	maxUnavailableValue := intstr.FromInt(maxUnavailable)
+
+	return &v1alpha1.NodePoolLabelSet{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "pool",
+			Namespace: testNamespace,
+			// the nodes were created after the set, but labeled by its previous generation
+			CreationTimestamp: meta_v1.NewTime(time.Now().Add(-time.Hour)),
+			Generation:        2,
+		},
+		Spec: v1alpha1.NodePoolLabelSetSpec{
+			Labels: map[string]string{"env": "new"},
+			Rollout: &v1alpha1.RolloutStrategy{
+				MaxUnavailable: &maxUnavailableValue,
+			},
+		},
+	}
+}
+
+func TestRolloutBatches(t *testing.T) {
+	logger := log.NewLogger(log.Config{Format: "logfmt", Level: "error"})
+	l := labeler.New(labeler.Config{}, k8sfake.NewSimpleClientset(), logger, emperror.NewNoopHandler())
+
+	previous := &labeler.Owner{Namespace: testNamespace, Name: "pool", Generation: 1}
+	other := &labeler.Owner{Namespace: testNamespace, Name: "other-pool", Generation: 1}
+	oldLabels := map[string]string{"env": "old"}
+
+	tests := []struct {
+		name           string
+		nodes          []*api_v1.Node
+		maxUnavailable int
+		// updated is the number of nodes with the new labels after each pass
+		updated []int
+	}{
+		{
+			name: "label change of existing nodes is applied one node per pass",
+			nodes: []*api_v1.Node{
+				testNode(t, "node-a", "pool", l, previous, oldLabels),
+				testNode(t, "node-b", "pool", l, previous, oldLabels),
+				testNode(t, "node-c", "pool", l, previous, oldLabels),
+			},
+			maxUnavailable: 1,
+			updated:        []int{1, 2, 3, 3},
+		},
+		{
+			name: "nodes never labeled by the set are labeled immediately",
+			nodes: []*api_v1.Node{
+				testNode(t, "node-a", "pool", l, previous, oldLabels),
+				testNode(t, "node-b", "pool", l, previous, oldLabels),
+				testNode(t, "node-c", "pool", l, nil, nil),
+				testNode(t, "node-d", "pool", l, other, map[string]string{"env": "other"}),
+			},
+			maxUnavailable: 1,
+			updated:        []int{3, 4, 4},
+		},
+		{
+			name: "unavailable nodes use up the batch",
+			nodes: []*api_v1.Node{
+				testNode(t, "node-a", "pool", l, previous, oldLabels),
+				testNode(t, "node-b", "pool", l, previous, oldLabels),
+				notReady(testNode(t, "node-c", "pool", l, previous, oldLabels)),
+			},
+			maxUnavailable: 1,
+			updated:        []int{1, 1},
+		},
+		{
+			name: "unavailable nodes are updated first",
+			nodes: []*api_v1.Node{
+				testNode(t, "node-a", "pool", l, previous, oldLabels),
+				testNode(t, "node-b", "pool", l, previous, oldLabels),
+				notReady(testNode(t, "node-c", "pool", l, previous, oldLabels)),
+			},
+			maxUnavailable: 2,
+			updated:        []int{2, 3},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			c := newTestController(t, test.nodes, testRolloutSet(test.maxUnavailable))
+			for pass, updated := range test.updated {
+				c.reconcile(t, "pool")
+
+				if got := c.labeledNodes(t, "env", "new"); got != updated {
+					t.Fatalf("pass %d: expected %d updated nodes, got %d", pass+1, updated, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRolloutWaitsForTheCacheOfTheLastBatch(t *testing.T) {
+	logger := log.NewLogger(log.Config{Format: "logfmt", Level: "error"})
+	l := labeler.New(labeler.Config{}, k8sfake.NewSimpleClientset(), logger, emperror.NewNoopHandler())
+	previous := &labeler.Owner{Namespace: testNamespace, Name: "pool", Generation: 1}
+	oldLabels := map[string]string{"env": "old"}
+
+	c := newTestController(t, []*api_v1.Node{
+		testNode(t, "node-a", "pool", l, previous, oldLabels),
+		testNode(t, "node-b", "pool", l, previous, oldLabels),
+		testNode(t, "node-c", "pool", l, previous, oldLabels),
+	}, testRolloutSet(1))
+	stale := c.nodes(t)
+
+	c.reconcile(t, "pool")
+	if got := c.labeledNodes(t, "env", "new"); got != 1 {
+		t.Fatalf("expected 1 updated node, got %d", got)
+	}
+
+	// the informer didn't catch up with the first batch yet
+	c.replaceNodes(t, stale)
+	c.k8sClient.ClearActions()
+	if err := c.reconcileNodepool(context.Background(), testNamespace+"/pool"); err != nil {
+		t.Fatal(err)
+	}
+	c.syncNPLSStore(t)
+
+	for _, action := range c.k8sClient.Actions() {
+		if action.GetResource().Resource == "nodes" && action.GetVerb() != "get" {
+			t.Errorf("expected the next batch to wait for the cache, got a %s of %s", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+	if got := c.labeledNodes(t, "env", "new"); got != 1 {
+		t.Fatalf("expected the next batch to wait for the cache, got %d updated nodes", got)
+	}
+	set, err := c.nplsClient.LabelsV1alpha1().NodePoolLabelSets(testNamespace).Get(context.Background(), "pool", meta_v1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.Status.Rollout.UpdatedNodes != 1 {
+		t.Fatalf("expected 1 updated node in the status, got %d", set.Status.Rollout.UpdatedNodes)
+	}
+
+	c.syncStores(t)
+	c.reconcile(t, "pool")
+	if got := c.labeledNodes(t, "env", "new"); got != 2 {
+		t.Fatalf("expected 2 updated nodes once the cache caught up, got %d", got)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	startupTaintTimeoutReason = "StartupTaintTimeout"
+)
+
+// handleStartupTaint removes the startup taint from a node once its labels are
+// applied, or when the configured timeout is over regardless of the sync result
+func (c *Controller) handleStartupTaint(ctx context.Context, node *api_v1.Node, syncErr error) {
+	if !c.startupTaint.Enabled || !hasTaint(node, c.startupTaint.Key) {
+		return
+	}
+
+	logger := c.logger.WithFields(map[string]interface{}{
+		"node":  node.Name,
+		"taint": c.startupTaint.Key,
+	})
+
+	if syncErr == nil {
+		logger.Info("labels applied, removing startup taint")
+		err := c.removeTaint(ctx, node.Name, c.startupTaint.Key)
+		if err != nil {
+			c.errorHandler.Handle(err)
+			c.workqueue.AddRateLimited(nodeKey(node.Name))
+		}
+		return
+	}
+
+	remaining := time.Until(node.CreationTimestamp.Add(c.startupTaint.Timeout))
+	if remaining > 0 {
+		c.workqueue.AddAfter(nodeKey(node.Name), remaining)
+		return
+	}
+
+	logger.Warn("startup taint timeout is over, removing startup taint")
+	err := c.removeTaint(ctx, node.Name, c.startupTaint.Key)
+	if err != nil {
+		c.errorHandler.Handle(err)
+		c.workqueue.AddRateLimited(nodeKey(node.Name))
+		return
+	}
+	c.recorder.Eventf(node, api_v1.EventTypeWarning, startupTaintTimeoutReason,
+		"startup taint %s removed after %s although the node pool labels could not be applied: %s", c.startupTaint.Key, c.startupTaint.Timeout, syncErr)
+}
+
+func (c *Controller) removeTaint(ctx context.Context, nodeName string, key string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := c.clientset.CoreV1().Nodes().Get(ctx, nodeName, meta_v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		taints := make([]api_v1.Taint, 0, len(node.Spec.Taints))
+		for _, taint := range node.Spec.Taints {
+			if taint.Key != key {
+				taints = append(taints, taint)
+			}
+		}
+		if len(taints) == len(node.Spec.Taints) {
+			return nil
+		}
+		node.Spec.Taints = taints
+
+		_, err = c.clientset.CoreV1().Nodes().Update(ctx, node, meta_v1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not remove taint", "node", nodeName, "taint", key)
+	}
+
+	return nil
+}
+
+func hasTaint(node *api_v1.Node, key string) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
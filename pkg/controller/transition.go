@@ -0,0 +1,64 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	api_v1 "k8s.io/api/core/v1"
+
+	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/labeler"
+)
+
+const (
+	nodepoolChangedReason = "NodepoolChanged"
+)
+
+// syncNode applies the labels of the current node pool of a node, the labels
+// set by the node pools the node was moved from are removed in the same patch
+func (c *Controller) syncNode(ctx context.Context, node *api_v1.Node, owner labeler.Owner, labelsToSet map[string]string) error {
+	previous := c.labeler.PreviousOwners(node, owner)
+
+	err := c.labeler.SyncLabels(ctx, node, owner, labelsToSet, previous...)
+	if err != nil || len(previous) == 0 {
+		return err
+	}
+
+	from := make([]string, 0, len(previous))
+	for _, owner := range previous {
+		from = append(from, owner.Name)
+	}
+	to := c.determineNodepoolNameFromNode(node)
+	if to == "" {
+		to = "<none>"
+	}
+
+	c.logger.WithFields(log.Fields{
+		"node": node.Name,
+		"from": strings.Join(from, ","),
+		"to":   to,
+	}).Info("node moved between node pools")
+	c.recorder.Eventf(node, api_v1.EventTypeNormal, nodepoolChangedReason,
+		"node moved from node pool %s to %s, labels of the previous node pool were replaced", strings.Join(from, ", "), to)
+
+	return nil
+}
+
+// isNodeUpToDate tells whether syncNode would leave the node unchanged
+func (c *Controller) isNodeUpToDate(node *api_v1.Node, owner labeler.Owner, labelsToSet map[string]string) bool {
+	return c.labeler.IsUpToDate(node, owner, labelsToSet, c.labeler.PreviousOwners(node, owner)...)
+}
@@ -0,0 +1,98 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"net/http"
+
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/controller"
+)
+
+// API serves the operator's view of node pools, nodes and the workqueue as JSON
+type API struct {
+	config     Config
+	controller *controller.Controller
+}
+
+// New gives back an initialized API
+func New(config Config, controller *controller.Controller) *API {
+	return &API{
+		config:     config,
+		controller: controller,
+	}
+}
+
+// RegisterRoutes registers the debug endpoints
+func (a *API) RegisterRoutes(r gin.IRouter) {
+	g := r.Group(a.config.PathPrefix)
+	g.GET("/nodepools", a.listNodepools)
+	g.GET("/nodepools/:name", a.getNodepool)
+	g.GET("/nodes/:name", a.getNode)
+	g.GET("/queue", a.getQueue)
+	g.GET("/patches", a.getPatchStats)
+}
+
+func (a *API) listNodepools(c *gin.Context) {
+	pools, err := a.controller.Nodepools()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pools)
+}
+
+func (a *API) getNodepool(c *gin.Context) {
+	pools, err := a.controller.Nodepools()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, pool := range pools {
+		if pool.Name == c.Param("name") {
+			c.JSON(http.StatusOK, pool)
+			return
+		}
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": "nodepool not found"})
+}
+
+func (a *API) getNode(c *gin.Context) {
+	node, err := a.controller.Node(c.Param("name"))
+	if k8serrors.IsNotFound(errors.Cause(err)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "node not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, node)
+}
+
+func (a *API) getQueue(c *gin.Context) {
+	c.JSON(http.StatusOK, a.controller.Queue())
+}
+
+func (a *API) getPatchStats(c *gin.Context) {
+	c.JSON(http.StatusOK, a.controller.PatchStats())
+}
@@ -12,28 +12,22 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package controller
+package debug
 
-type eventType string
+import "emperror.dev/errors"
 
-const (
-	UpdateEvent eventType = "Update"
-	DeleteEvent eventType = "Delete"
-	AddEvent    eventType = "Add"
-)
-
-// Event describes a system event
-type Event struct {
-	resourceType string
-	eventType    eventType
-	key          string
+type Config struct {
+	// Enabled turns on the read-only debug endpoints on the health check server
+	Enabled bool `mapstructure:"enabled"`
+	// PathPrefix is the URL path prefix of the debug endpoints
+	PathPrefix string `mapstructure:"pathPrefix"`
 }
 
-// NewEvent create a new system event
-func NewEvent(resourceType string, eventType eventType, key string) *Event {
-	return &Event{
-		resourceType: resourceType,
-		eventType:    eventType,
-		key:          key,
+// Validate checks that the configuration is valid.
+func (c Config) Validate() error {
+	if c.Enabled && c.PathPrefix == "" {
+		return errors.New("path prefix must not be empty")
 	}
+
+	return nil
 }
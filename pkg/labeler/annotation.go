@@ -0,0 +1,175 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labeler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// managedLabelsFormatVersion is the version of the managed labels annotation
+	// format, the legacy format is a bare JSON array of the label names
+	managedLabelsFormatVersion = "v2"
+)
+
+// errUnsupportedFormatVersion is returned for managed labels records of an
+// unknown format version, e.g. written by a newer operator, these records are
+// left intact instead of being overwritten
+const errUnsupportedFormatVersion = errors.Sentinel("unsupported managed labels format version")
+
+// Owner identifies the NodePoolLabelSet whose labels are applied to a node
+// and its generation at the time the labels were set
+type Owner struct {
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Generation int64  `json:"generation,omitempty"`
+}
+
+func (o Owner) String() string {
+	if o.Namespace == "" {
+		return o.Name
+	}
+
+	return o.Namespace + "/" + o.Name
+}
+
+// sameSet tells whether both owners identify the same NodePoolLabelSet regardless of its generation
+func (o Owner) sameSet(other Owner) bool {
+	return o.Namespace == other.Namespace && o.Name == other.Name
+}
+
+func parseOwner(value string) Owner {
+	if i := strings.Index(value, "/"); i >= 0 {
+		return Owner{Namespace: value[:i], Name: value[i+1:]}
+	}
+
+	return Owner{Name: value}
+}
+
+// managedLabelsRecord is the content of the managed labels annotation, Owner
+// is the NodePoolLabelSet which reconciled the node last and Owners holds the
+// NodePoolLabelSet which set each managed label
+type managedLabelsRecord struct {
+	Version string           `json:"version"`
+	Owner   string           `json:"owner,omitempty"`
+	Hash    string           `json:"hash"`
+	Labels  []string         `json:"labels"`
+	Owners  map[string]Owner `json:"owners,omitempty"`
+}
+
+func newManagedLabelsRecord(owner Owner, owners map[string]Owner, nodeLabels map[string]string) managedLabelsRecord {
+	labels := make([]string, 0, len(owners))
+	for label := range owners {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	return managedLabelsRecord{
+		Version: managedLabelsFormatVersion,
+		Owner:   owner.String(),
+		Hash:    hashLabels(labels, nodeLabels),
+		Labels:  labels,
+		Owners:  owners,
+	}
+}
+
+// ownerOf gives back the owner of a managed label, records written before
+// per label ownership was tracked are owned by the owner of the record
+func (r managedLabelsRecord) ownerOf(label string) (Owner, bool) {
+	if owner, ok := r.Owners[label]; ok {
+		return owner, true
+	}
+
+	if r.Owner != "" {
+		return parseOwner(r.Owner), true
+	}
+
+	return Owner{}, false
+}
+
+// isOwnedBy tells whether a managed label was set by the given owner, labels
+// of legacy records without an owner belong to every owner
+func (r managedLabelsRecord) isOwnedBy(label string, owner Owner) bool {
+	labelOwner, ok := r.ownerOf(label)
+
+	return !ok || labelOwner.sameSet(owner)
+}
+
+// hashLabels gives back a hash of the values of the given labels
+func hashLabels(labels []string, values map[string]string) string {
+	h := sha256.New()
+	for _, label := range labels {
+		fmt.Fprintf(h, "%s=%s\n", label, values[label])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func parseManagedLabelsRecord(value string) (managedLabelsRecord, error) {
+	var record managedLabelsRecord
+
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") {
+		err := json.Unmarshal([]byte(value), &record.Labels)
+		if err != nil {
+			return record, errors.WrapIf(err, "could not unmarshal legacy annotation")
+		}
+
+		return record, nil
+	}
+
+	err := json.Unmarshal([]byte(value), &record)
+	if err != nil {
+		return record, errors.WrapIf(err, "could not unmarshal annotation")
+	}
+
+	if record.Version != managedLabelsFormatVersion {
+		return managedLabelsRecord{}, errors.WithDetails(errUnsupportedFormatVersion, "version", record.Version)
+	}
+
+	return record, nil
+}
+
+// readManagedLabels gives back the managed labels record of the node from the
+// current annotation or, if it is missing, from the first legacy annotation
+// present, along with the name of the annotation it was read from
+func (l *Labeler) readManagedLabels(node *api_v1.Node) (managedLabelsRecord, string, error) {
+	current, legacy := l.annotations()
+	nodeAnnotations := node.GetAnnotations()
+
+	for _, annotation := range append([]string{current}, legacy...) {
+		value, ok := nodeAnnotations[annotation]
+		if !ok {
+			continue
+		}
+
+		record, err := parseManagedLabelsRecord(value)
+		if err != nil {
+			return record, annotation, errors.WrapIfWithDetails(err, "could not parse managed labels", "annotation", annotation)
+		}
+
+		return record, annotation, nil
+	}
+
+	return managedLabelsRecord{}, "", nil
+}
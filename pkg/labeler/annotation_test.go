@@ -0,0 +1,78 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labeler
+
+import (
+	"reflect"
+	"testing"
+
+	"emperror.dev/errors"
+)
+
+func TestParseManagedLabelsRecord(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		record      managedLabelsRecord
+		unsupported bool
+	}{
+		{
+			name:   "legacy format",
+			value:  `["a","b"]`,
+			record: managedLabelsRecord{Labels: []string{"a", "b"}},
+		},
+		{
+			name:  "current format",
+			value: `{"version":"v2","owner":"default/pool","hash":"x","labels":["a"],"owners":{"a":{"namespace":"default","name":"pool","generation":1}}}`,
+			record: managedLabelsRecord{
+				Version: managedLabelsFormatVersion,
+				Owner:   "default/pool",
+				Hash:    "x",
+				Labels:  []string{"a"},
+				Owners:  map[string]Owner{"a": {Namespace: "default", Name: "pool", Generation: 1}},
+			},
+		},
+		{
+			name:        "newer format",
+			value:       `{"version":"v3","labels":["a"]}`,
+			unsupported: true,
+		},
+		{
+			name:        "missing version",
+			value:       `{"labels":["a"]}`,
+			unsupported: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			record, err := parseManagedLabelsRecord(test.value)
+			if test.unsupported {
+				if !errors.Is(err, errUnsupportedFormatVersion) {
+					t.Fatalf("expected an unsupported format version error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(record, test.record) {
+				t.Errorf("expected record %+v, got %+v", test.record, record)
+			}
+		})
+	}
+}
@@ -14,9 +14,80 @@
 
 package labeler
 
+import (
+	"strings"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
 type Config struct {
 	// ManagedLabelsAnnotation is name name of annotation which holds the managed labels
 	ManagedLabelsAnnotation string `mapstructure:"managedLabelsAnnotation"`
+	// LegacyManagedLabelsAnnotations holds previously used managed labels annotation
+	// names which are read if the current one is missing and migrated to it
+	LegacyManagedLabelsAnnotations []string `mapstructure:"legacyManagedLabelsAnnotations"`
 	// ForbiddenLabelDomains holds the forbidden domain names, the labeler won't set matching labels
 	ForbiddenLabelDomains []string `mapstructure:"forbiddenLabelDomains"`
+	// OptimisticConcurrency makes node patches fail if the node changed since it was
+	// read, in which case the labels are computed again from a fresh read and retried
+	OptimisticConcurrency bool `mapstructure:"optimisticConcurrency"`
+}
+
+// Validate checks that the configuration is valid.
+func (c Config) Validate() error {
+	if c.ManagedLabelsAnnotation != "" {
+		if errs := validation.IsQualifiedName(c.ManagedLabelsAnnotation); len(errs) > 0 {
+			return errors.Errorf("invalid managed labels annotation %q: %s", c.ManagedLabelsAnnotation, strings.Join(errs, ", "))
+		}
+	}
+
+	for _, annotation := range c.LegacyManagedLabelsAnnotations {
+		if errs := validation.IsQualifiedName(annotation); len(errs) > 0 {
+			return errors.Errorf("invalid legacy managed labels annotation %q: %s", annotation, strings.Join(errs, ", "))
+		}
+		if annotation == c.managedLabelsAnnotation() {
+			return errors.Errorf("legacy managed labels annotation %q must differ from the current one", annotation)
+		}
+	}
+
+	for _, domain := range c.ForbiddenLabelDomains {
+		if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+			return errors.Errorf("invalid forbidden label domain %q: %s", domain, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+// managedLabelsAnnotation gives back the name of the managed labels annotation
+// in effect, which is the default one if none is set
+func (c Config) managedLabelsAnnotation() string {
+	if c.ManagedLabelsAnnotation == "" {
+		return managedLabelsAnnotation
+	}
+
+	return c.ManagedLabelsAnnotation
+}
+
+// ReadsRecordsOf tells whether a labeler with the configuration reads every
+// managed labels record written with the previous configuration, so switching
+// to it at runtime doesn't orphan the records on the nodes
+func (c Config) ReadsRecordsOf(previous Config) bool {
+	readable := make(map[string]bool, len(c.LegacyManagedLabelsAnnotations)+1)
+	readable[c.managedLabelsAnnotation()] = true
+	for _, annotation := range c.LegacyManagedLabelsAnnotations {
+		readable[annotation] = true
+	}
+
+	if !readable[previous.managedLabelsAnnotation()] {
+		return false
+	}
+	for _, annotation := range previous.LegacyManagedLabelsAnnotations {
+		if !readable[annotation] {
+			return false
+		}
+	}
+
+	return true
 }
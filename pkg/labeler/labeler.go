@@ -17,15 +17,20 @@ package labeler
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"emperror.dev/emperror"
 	"emperror.dev/errors"
 	api_v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
 
 	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
 )
@@ -36,8 +41,15 @@ const (
 
 // Labeler describes the node labeler
 type Labeler struct {
-	managedLabelsAnnotation string
-	forbiddenLabelDomains   []string
+	mu                             sync.RWMutex
+	managedLabelsAnnotation        string
+	legacyManagedLabelsAnnotations []string
+	forbiddenLabelDomains          []string
+	optimisticConcurrency          bool
+
+	// accessed atomically
+	appliedPatches int64
+	skippedPatches int64
 
 	clientset    kubernetes.Interface
 	logger       log.Logger
@@ -46,89 +58,342 @@ type Labeler struct {
 
 // New gives back an initialized Labeler
 func New(config Config, clientset kubernetes.Interface, logger log.Logger, errorHandler emperror.Handler) *Labeler {
-	annotation := config.ManagedLabelsAnnotation
-	if annotation == "" {
-		annotation = managedLabelsAnnotation
-	}
-
-	return &Labeler{
-		managedLabelsAnnotation: annotation,
-		forbiddenLabelDomains:   config.ForbiddenLabelDomains,
-
+	l := &Labeler{
 		clientset:    clientset,
 		logger:       logger,
 		errorHandler: errorHandler,
 	}
+	l.UpdateConfig(config)
+
+	return l
+}
+
+// UpdateConfig changes the managed labels annotations and the forbidden label
+// domains of a running labeler
+func (l *Labeler) UpdateConfig(config Config) {
+	annotation := config.managedLabelsAnnotation()
+
+	// the current record would be deleted along with a legacy one of the same name
+	legacy := make([]string, 0, len(config.LegacyManagedLabelsAnnotations))
+	for _, legacyAnnotation := range config.LegacyManagedLabelsAnnotations {
+		if legacyAnnotation != annotation {
+			legacy = append(legacy, legacyAnnotation)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.managedLabelsAnnotation = annotation
+	l.legacyManagedLabelsAnnotations = legacy
+	l.forbiddenLabelDomains = config.ForbiddenLabelDomains
+	l.optimisticConcurrency = config.OptimisticConcurrency
 }
 
-// SyncLabels syncs node labels
-func (l *Labeler) SyncLabels(node *api_v1.Node, labelsToSet map[string]string) error {
+// SyncLabels syncs node labels, the managed labels set by the released owners
+// are removed in the same patch, no patch is sent if the node is up to date
+func (l *Labeler) SyncLabels(ctx context.Context, node *api_v1.Node, owner Owner, labelsToSet map[string]string, release ...Owner) error {
 	l.logger.WithField("node", node.Name).Debug("sync labels")
 
-	oldData, err := json.Marshal(*node)
+	l.mu.RLock()
+	optimistic := l.optimisticConcurrency
+	l.mu.RUnlock()
+
+	if !optimistic {
+		return l.patchNode(ctx, node, owner, labelsToSet, release, false)
+	}
+
+	fresh := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if fresh {
+			current, err := l.clientset.CoreV1().Nodes().Get(ctx, node.Name, v1.GetOptions{})
+			if err != nil {
+				return errors.WrapIf(err, "could not get node")
+			}
+			*node = *current
+		}
+		fresh = true
+
+		return l.patchNode(ctx, node, owner, labelsToSet, release, true)
+	})
+	if k8serrors.IsConflict(err) {
+		return errors.WrapIfWithDetails(err, "could not patch node, it keeps changing", "node", node.Name)
+	}
+
+	return err
+}
+
+// patchNode applies the labels on the node object and sends the resulting patch,
+// which is conditional on the resource version of the node if requested
+func (l *Labeler) patchNode(ctx context.Context, node *api_v1.Node, owner Owner, labelsToSet map[string]string, release []Owner, conditional bool) error {
+	original := node.DeepCopy()
+	err := l.ApplyLabels(node, owner, labelsToSet, release...)
 	if err != nil {
-		return errors.WrapIf(err, "could not marshal old node object")
+		return err
+	}
+
+	var resourceVersion string
+	if conditional {
+		resourceVersion = original.ResourceVersion
 	}
 
-	nodeLabels, managedLabels := l.getDesiredLabels(node, labelsToSet)
-	annotations, err := l.updateAnnotations(node.GetAnnotations(), managedLabels)
+	patch, err := computePatch(original, node, resourceVersion)
 	if err != nil {
-		return errors.WrapIf(err, "could not update annotations")
+		return errors.WrapIf(err, "could not compute patch")
+	}
+
+	if patch == nil {
+		atomic.AddInt64(&l.skippedPatches, 1)
+		l.logger.WithField("node", node.Name).Debug("node is up to date, skipping patch")
+		return nil
 	}
-	node.SetAnnotations(annotations)
-	node.SetLabels(nodeLabels)
 
-	newData, err := json.Marshal(*node)
+	_, err = l.clientset.CoreV1().Nodes().Patch(ctx, node.Name, types.MergePatchType, patch, v1.PatchOptions{})
+	if k8serrors.IsConflict(err) {
+		// returned as is to be retried
+		return err
+	}
 	if err != nil {
-		return errors.WrapIf(err, "could not marshal new node object")
+		return errors.WrapIf(err, "could not patch node")
 	}
+	atomic.AddInt64(&l.appliedPatches, 1)
 
-	patch, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, *node)
+	return nil
+}
+
+// Diff gives back the JSON merge patch SyncLabels would send for the node, or
+// nil if the node is up to date
+func (l *Labeler) Diff(node *api_v1.Node, owner Owner, labelsToSet map[string]string, release ...Owner) ([]byte, error) {
+	nodeLabels, record, err := l.desiredState(node, owner, labelsToSet, release)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := l.updateAnnotations(node.GetAnnotations(), record)
 	if err != nil {
-		return errors.WrapIf(err, "could not create two way merge patch")
+		return nil, errors.WrapIf(err, "could not update annotations")
+	}
+
+	desired := node.DeepCopy()
+	desired.SetLabels(nodeLabels)
+	desired.SetAnnotations(annotations)
+
+	return ComputePatch(node, desired)
+}
+
+// PatchStats gives back the number of applied and skipped node patches
+func (l *Labeler) PatchStats() PatchStats {
+	return PatchStats{
+		Applied: atomic.LoadInt64(&l.appliedPatches),
+		Skipped: atomic.LoadInt64(&l.skippedPatches),
 	}
+}
 
-	_, err = l.clientset.CoreV1().Nodes().Patch(context.TODO(), node.Name, types.MergePatchType, patch, v1.PatchOptions{})
+// ApplyLabels sets the desired labels and the managed labels annotation on the
+// given node object without persisting it, legacy annotations are migrated
+func (l *Labeler) ApplyLabels(node *api_v1.Node, owner Owner, labelsToSet map[string]string, release ...Owner) error {
+	nodeLabels, record, err := l.desiredState(node, owner, labelsToSet, release)
 	if err != nil {
-		return errors.WrapIf(err, "could not patch node")
+		return err
+	}
+	annotations, err := l.updateAnnotations(node.GetAnnotations(), record)
+	if err != nil {
+		return errors.WrapIf(err, "could not update annotations")
 	}
 
+	l.logChanges(node, nodeLabels, labelsToSet)
+
+	node.SetAnnotations(annotations)
+	node.SetLabels(nodeLabels)
+
 	return nil
 }
 
-func (l *Labeler) updateAnnotations(currentAnnotations map[string]string, managedLabels []string) (map[string]string, error) {
-	if currentAnnotations == nil {
-		currentAnnotations = make(map[string]string)
+// IsUpToDate tells whether the node already has the desired labels, no stale
+// managed labels and a current managed labels annotation
+func (l *Labeler) IsUpToDate(node *api_v1.Node, owner Owner, labelsToSet map[string]string, release ...Owner) bool {
+	patch, err := l.Diff(node, owner, labelsToSet, release...)
+
+	return err == nil && patch == nil
+}
+
+// ManagedLabels gives back the labels of the node which are managed by the labeler
+func (l *Labeler) ManagedLabels(node *api_v1.Node) []string {
+	record, _, _ := l.readManagedLabels(node)
+
+	return record.Labels
+}
+
+// LabelOwners gives back the node pool label set which set each managed label of the node
+func (l *Labeler) LabelOwners(node *api_v1.Node) map[string]Owner {
+	record, _, _ := l.readManagedLabels(node)
+
+	owners := make(map[string]Owner, len(record.Labels))
+	for _, label := range record.Labels {
+		if owner, ok := record.ownerOf(label); ok {
+			owners[label] = owner
+		}
+	}
+
+	return owners
+}
+
+// IsLabeledBy tells whether the managed labels record of the node was written
+// by the given owner or holds labels set by it, regardless of its generation
+func (l *Labeler) IsLabeledBy(node *api_v1.Node, owner Owner) bool {
+	record, _, _ := l.readManagedLabels(node)
+
+	if record.Owner != "" && parseOwner(record.Owner).sameSet(owner) {
+		return true
+	}
+
+	for _, label := range record.Labels {
+		if record.isOwnedBy(label, owner) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PreviousOwners gives back the owners of the managed labels of the node other
+// than the given one, these are the node pools the node was moved from
+func (l *Labeler) PreviousOwners(node *api_v1.Node, owner Owner) []Owner {
+	record, _, _ := l.readManagedLabels(node)
+
+	seen := make(map[string]bool)
+	owners := make([]Owner, 0)
+	for _, label := range record.Labels {
+		labelOwner, ok := record.ownerOf(label)
+		if !ok || labelOwner.sameSet(owner) || seen[labelOwner.String()] {
+			continue
+		}
+		seen[labelOwner.String()] = true
+		owners = append(owners, labelOwner)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		return owners[i].String() < owners[j].String()
+	})
+
+	return owners
+}
+
+// AllowedLabels gives back the labels which the labeler would set on a node,
+// forbidden and invalid labels are left out
+func (l *Labeler) AllowedLabels(labelsToSet map[string]string) map[string]string {
+	allowed := make(map[string]string, len(labelsToSet))
+	for label, value := range labelsToSet {
+		if l.isLabelAllowed(label) && validateLabel(label, value) == "" {
+			allowed[label] = value
+		}
+	}
+
+	return allowed
+}
+
+// InvalidLabels gives back the labels which are not valid Kubernetes labels
+// along with the reason, these are never set on nodes
+func (l *Labeler) InvalidLabels(labelsToSet map[string]string) map[string]string {
+	invalid := make(map[string]string)
+	for label, value := range labelsToSet {
+		if reason := validateLabel(label, value); reason != "" {
+			invalid[label] = reason
+		}
+	}
+
+	return invalid
+}
+
+func (l *Labeler) updateAnnotations(currentAnnotations map[string]string, record managedLabelsRecord) (map[string]string, error) {
+	annotations := make(map[string]string, len(currentAnnotations)+1)
+	for key, value := range currentAnnotations {
+		annotations[key] = value
 	}
 
-	managedLabelsJSON, err := json.Marshal(managedLabels)
+	recordJSON, err := json.Marshal(record)
 	if err != nil {
-		return currentAnnotations, errors.WrapIf(err, "could not marshal managed labels to annotation")
+		return annotations, errors.WrapIf(err, "could not marshal managed labels to annotation")
+	}
+
+	current, legacy := l.annotations()
+	annotations[current] = string(recordJSON)
+	for _, annotation := range legacy {
+		delete(annotations, annotation)
 	}
-	currentAnnotations[l.managedLabelsAnnotation] = string(managedLabelsJSON)
 
-	return currentAnnotations, nil
+	return annotations, nil
 }
 
-func (l *Labeler) getDesiredLabels(node *api_v1.Node, labelsToSet map[string]string) (map[string]string, []string) {
-	logger := l.logger.WithField("node", node.Name)
-	managedLabels, _ := l.getManagedLabels(node)
-	nodeLabels := node.GetLabels()
-	mLabels := make(map[string]bool, len(managedLabels))
-	for _, label := range managedLabels {
-		mLabels[label] = true
+// desiredState gives back the labels and the managed labels record the node
+// should have without modifying the node, managed labels which are no longer
+// desired are only removed if they are owned by the given or a released owner.
+// Unparsable records are replaced, records of an unknown format version are
+// not, an error is returned instead.
+func (l *Labeler) desiredState(node *api_v1.Node, owner Owner, labelsToSet map[string]string, release []Owner) (map[string]string, managedLabelsRecord, error) {
+	current, _, err := l.readManagedLabels(node)
+	if errors.Is(err, errUnsupportedFormatVersion) {
+		return nil, current, errors.WrapIfWithDetails(err, "could not read managed labels", "node", node.Name)
 	}
 
-	for label := range nodeLabels {
-		if mLabels[label] && len(labelsToSet[label]) == 0 {
+	nodeLabels := make(map[string]string, len(node.GetLabels())+len(labelsToSet))
+	for label, value := range node.GetLabels() {
+		nodeLabels[label] = value
+	}
+
+	desiredLabels := l.AllowedLabels(labelsToSet)
+	owners := make(map[string]Owner, len(desiredLabels))
+
+	for _, label := range current.Labels {
+		if _, ok := desiredLabels[label]; ok {
+			continue
+		}
+		if !current.isOwnedBy(label, owner) && !isReleased(current, label, release) {
+			// set by another node pool label set, keep tracking it
+			labelOwner, _ := current.ownerOf(label)
+			if _, ok := nodeLabels[label]; ok {
+				owners[label] = labelOwner
+			}
+			continue
+		}
+		delete(nodeLabels, label)
+	}
+
+	for label, value := range desiredLabels {
+		labelOwner := owner
+		if previous, ok := current.Owners[label]; ok && previous.sameSet(owner) {
+			if currentValue, ok := nodeLabels[label]; ok && currentValue == value {
+				// unchanged labels keep the generation they were set at
+				labelOwner.Generation = previous.Generation
+			}
+		}
+		nodeLabels[label] = value
+		owners[label] = labelOwner
+	}
+
+	return nodeLabels, newManagedLabelsRecord(owner, owners, nodeLabels), nil
+}
+
+func isReleased(record managedLabelsRecord, label string, release []Owner) bool {
+	for _, owner := range release {
+		if record.isOwnedBy(label, owner) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *Labeler) logChanges(node *api_v1.Node, nodeLabels map[string]string, labelsToSet map[string]string) {
+	logger := l.logger.WithField("node", node.Name)
+	currentLabels := node.GetLabels()
+
+	for label := range currentLabels {
+		if _, ok := nodeLabels[label]; !ok {
 			logger.WithField("label", label).Info("removing label")
-			delete(nodeLabels, label)
 		}
 	}
 
-	managedLabels = make([]string, 0)
 	for label, value := range labelsToSet {
-		logger = logger.WithFields(log.Fields{
+		logger := logger.WithFields(log.Fields{
 			"label":      label,
 			"labelValue": value,
 		})
@@ -136,29 +401,48 @@ func (l *Labeler) getDesiredLabels(node *api_v1.Node, labelsToSet map[string]str
 			logger.Info("forbidden label")
 			continue
 		}
-		managedLabels = append(managedLabels, label)
-		if currentValue, ok := nodeLabels[label]; ok && currentValue == value {
+		if reason := validateLabel(label, value); reason != "" {
+			logger.WithField("reason", reason).Warn("invalid label")
 			continue
 		}
-		logger.Info("setting label")
-		nodeLabels[label] = value
+		if currentValue, ok := currentLabels[label]; !ok || currentValue != value {
+			logger.Info("setting label")
+		}
 	}
-
-	return nodeLabels, managedLabels
 }
 
-func (l *Labeler) getManagedLabels(node *api_v1.Node) ([]string, error) {
-	var labels []string
+// validateLabel gives back why a label is not a valid Kubernetes label, or an
+// empty string if it is valid
+func validateLabel(label string, value string) string {
+	if errs := validation.IsQualifiedName(label); len(errs) > 0 {
+		return "invalid key: " + strings.Join(errs, ", ")
+	}
 
-	err := json.Unmarshal([]byte(node.GetAnnotations()[l.managedLabelsAnnotation]), &labels)
-	if err != nil {
-		return labels, errors.WrapIf(err, "could not unmarshal annotation")
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return "invalid value: " + strings.Join(errs, ", ")
 	}
 
-	return labels, nil
+	return ""
+}
+
+func (l *Labeler) annotation() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.managedLabelsAnnotation
+}
+
+func (l *Labeler) annotations() (string, []string) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.managedLabelsAnnotation, l.legacyManagedLabelsAnnotations
 }
 
 func (l *Labeler) isLabelAllowed(label string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
 	for _, domain := range l.forbiddenLabelDomains {
 		if strings.Contains(label, domain) {
 			return false
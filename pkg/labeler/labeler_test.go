@@ -0,0 +1,151 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labeler
+
+import (
+	"reflect"
+	"testing"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
+)
+
+func newTestLabeler(config Config) *Labeler {
+	logger := log.NewLogger(log.Config{Format: "logfmt", Level: "error"})
+
+	return New(config, fake.NewSimpleClientset(), logger, emperror.NewNoopHandler())
+}
+
+func TestApplyLabelsMigratesManagedLabels(t *testing.T) {
+	owner := Owner{Namespace: "default", Name: "pool", Generation: 2}
+	other := Owner{Namespace: "default", Name: "other-pool", Generation: 1}
+
+	tests := []struct {
+		name        string
+		config      Config
+		labels      map[string]string
+		annotations map[string]string
+		labelsToSet map[string]string
+
+		expectedLabels     map[string]string
+		expectedAnnotation string
+		removedAnnotations []string
+		expectedOwners     map[string]Owner
+	}{
+		{
+			name:               "legacy format labels belong to every owner",
+			labels:             map[string]string{"a": "1", "b": "1", "c": "1"},
+			annotations:        map[string]string{managedLabelsAnnotation: `["a","b"]`},
+			labelsToSet:        map[string]string{"a": "2"},
+			expectedLabels:     map[string]string{"a": "2", "c": "1"},
+			expectedAnnotation: managedLabelsAnnotation,
+			expectedOwners:     map[string]Owner{"a": owner},
+		},
+		{
+			name:               "labels of other owners are kept",
+			labels:             map[string]string{"a": "1", "b": "1"},
+			annotations:        map[string]string{managedLabelsAnnotation: `{"version":"v2","owner":"default/other-pool","labels":["b"],"owners":{"b":{"namespace":"default","name":"other-pool","generation":1}}}`},
+			labelsToSet:        map[string]string{"a": "2"},
+			expectedLabels:     map[string]string{"a": "2", "b": "1"},
+			expectedAnnotation: managedLabelsAnnotation,
+			expectedOwners:     map[string]Owner{"a": owner, "b": other},
+		},
+		{
+			name: "legacy annotation name is migrated",
+			config: Config{
+				ManagedLabelsAnnotation:        "example.com/managed-labels",
+				LegacyManagedLabelsAnnotations: []string{managedLabelsAnnotation},
+			},
+			labels:             map[string]string{"a": "1", "b": "1"},
+			annotations:        map[string]string{managedLabelsAnnotation: `["a","b"]`},
+			labelsToSet:        map[string]string{"a": "1"},
+			expectedLabels:     map[string]string{"a": "1"},
+			expectedAnnotation: "example.com/managed-labels",
+			removedAnnotations: []string{managedLabelsAnnotation},
+			expectedOwners:     map[string]Owner{"a": owner},
+		},
+		{
+			name: "legacy annotation name equal to the default one is ignored",
+			config: Config{
+				LegacyManagedLabelsAnnotations: []string{managedLabelsAnnotation},
+			},
+			labels:             map[string]string{"a": "1"},
+			labelsToSet:        map[string]string{"a": "1"},
+			expectedLabels:     map[string]string{"a": "1"},
+			expectedAnnotation: managedLabelsAnnotation,
+			expectedOwners:     map[string]Owner{"a": owner},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			l := newTestLabeler(test.config)
+			node := testPatchNode(test.labels, test.annotations)
+
+			err := l.ApplyLabels(node, owner, test.labelsToSet)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(node.Labels, test.expectedLabels) {
+				t.Errorf("expected labels %v, got %v", test.expectedLabels, node.Labels)
+			}
+
+			value, ok := node.Annotations[test.expectedAnnotation]
+			if !ok {
+				t.Fatalf("expected annotation %s, got %v", test.expectedAnnotation, node.Annotations)
+			}
+			record, err := parseManagedLabelsRecord(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if record.Version != managedLabelsFormatVersion {
+				t.Errorf("expected record version %s, got %s", managedLabelsFormatVersion, record.Version)
+			}
+			if !reflect.DeepEqual(record.Owners, test.expectedOwners) {
+				t.Errorf("expected label owners %v, got %v", test.expectedOwners, record.Owners)
+			}
+
+			for _, annotation := range test.removedAnnotations {
+				if _, ok := node.Annotations[annotation]; ok {
+					t.Errorf("expected annotation %s to be removed", annotation)
+				}
+			}
+
+			if !l.IsUpToDate(node, owner, test.labelsToSet) {
+				t.Error("expected node to be up to date after applying the labels")
+			}
+		})
+	}
+}
+
+func TestApplyLabelsKeepsRecordsOfUnknownVersions(t *testing.T) {
+	l := newTestLabeler(Config{})
+	annotations := map[string]string{managedLabelsAnnotation: `{"version":"v3","labels":["a"]}`}
+	node := testPatchNode(map[string]string{"a": "1"}, annotations)
+
+	err := l.ApplyLabels(node, Owner{Namespace: "default", Name: "pool"}, map[string]string{"b": "1"})
+	if !errors.Is(err, errUnsupportedFormatVersion) {
+		t.Fatalf("expected an unsupported format version error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(node.Labels, map[string]string{"a": "1"}) || !reflect.DeepEqual(node.Annotations, annotations) {
+		t.Errorf("expected the node to be left intact, got labels %v and annotations %v", node.Labels, node.Annotations)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labeler
+
+import (
+	"encoding/json"
+
+	"emperror.dev/errors"
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// PatchStats counts the node patches which were sent and the ones which were
+// skipped because the node was already up to date
+type PatchStats struct {
+	Applied int64 `json:"applied"`
+	Skipped int64 `json:"skipped"`
+}
+
+// ComputePatch gives back the JSON merge patch which turns the labels and
+// annotations of the current node object into the ones of the desired node
+// object, or nil if they are equal
+func ComputePatch(current *api_v1.Node, desired *api_v1.Node) ([]byte, error) {
+	return computePatch(current, desired, "")
+}
+
+// computePatch works like ComputePatch, a non-empty patch is made conditional
+// on the given resource version
+func computePatch(current *api_v1.Node, desired *api_v1.Node, resourceVersion string) ([]byte, error) {
+	metadata := make(map[string]interface{})
+
+	if diff := diffMaps(current.GetLabels(), desired.GetLabels()); len(diff) > 0 {
+		metadata["labels"] = diff
+	}
+
+	if diff := diffMaps(current.GetAnnotations(), desired.GetAnnotations()); len(diff) > 0 {
+		metadata["annotations"] = diff
+	}
+
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+
+	if resourceVersion != "" {
+		metadata["resourceVersion"] = resourceVersion
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": metadata,
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not marshal patch")
+	}
+
+	return patch, nil
+}
+
+// diffMaps gives back the changed and added keys with their new value and the
+// removed keys with a nil value
+func diffMaps(current map[string]string, desired map[string]string) map[string]interface{} {
+	diff := make(map[string]interface{})
+
+	for key, value := range desired {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			diff[key] = value
+		}
+	}
+
+	for key := range current {
+		if _, ok := desired[key]; !ok {
+			diff[key] = nil
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,127 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labeler
+
+import (
+	"testing"
+
+	api_v1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPatchNode(labels map[string]string, annotations map[string]string) *api_v1.Node {
+	return &api_v1.Node{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "node",
+			Labels:      labels,
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestComputePatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		current         *api_v1.Node
+		desired         *api_v1.Node
+		resourceVersion string
+		patch           string
+	}{
+		{
+			name:    "equal nodes need no patch",
+			current: testPatchNode(map[string]string{"a": "1"}, map[string]string{"x": "1"}),
+			desired: testPatchNode(map[string]string{"a": "1"}, map[string]string{"x": "1"}),
+		},
+		{
+			name:            "empty patch is not made conditional",
+			current:         testPatchNode(nil, nil),
+			desired:         testPatchNode(map[string]string{}, map[string]string{}),
+			resourceVersion: "42",
+		},
+		{
+			name:    "added and changed labels",
+			current: testPatchNode(map[string]string{"a": "1", "b": "1"}, nil),
+			desired: testPatchNode(map[string]string{"a": "1", "b": "2", "c": "3"}, nil),
+			patch:   `{"metadata":{"labels":{"b":"2","c":"3"}}}`,
+		},
+		{
+			name:    "removed labels are deleted with null",
+			current: testPatchNode(map[string]string{"a": "1", "b": "2"}, nil),
+			desired: testPatchNode(map[string]string{"a": "1"}, nil),
+			patch:   `{"metadata":{"labels":{"b":null}}}`,
+		},
+		{
+			name:    "every label removed",
+			current: testPatchNode(map[string]string{"a": "1"}, nil),
+			desired: testPatchNode(nil, nil),
+			patch:   `{"metadata":{"labels":{"a":null}}}`,
+		},
+		{
+			name:    "annotation only diff",
+			current: testPatchNode(map[string]string{"a": "1"}, map[string]string{"x": "1", "y": "1"}),
+			desired: testPatchNode(map[string]string{"a": "1"}, map[string]string{"x": "2"}),
+			patch:   `{"metadata":{"annotations":{"x":"2","y":null}}}`,
+		},
+		{
+			name:    "label and annotation diff",
+			current: testPatchNode(nil, nil),
+			desired: testPatchNode(map[string]string{"a": "1"}, map[string]string{"x": "1"}),
+			patch:   `{"metadata":{"annotations":{"x":"1"},"labels":{"a":"1"}}}`,
+		},
+		{
+			name:            "resource version precondition",
+			current:         testPatchNode(map[string]string{"a": "1"}, nil),
+			desired:         testPatchNode(map[string]string{"a": "2"}, nil),
+			resourceVersion: "42",
+			patch:           `{"metadata":{"labels":{"a":"2"},"resourceVersion":"42"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			patch, err := computePatch(test.current, test.desired, test.resourceVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.patch == "" {
+				if patch != nil {
+					t.Fatalf("expected no patch, got %s", patch)
+				}
+				return
+			}
+
+			if string(patch) != test.patch {
+				t.Fatalf("expected patch %s, got %s", test.patch, patch)
+			}
+		})
+	}
+}
+
+func TestComputePatchIsUnconditional(t *testing.T) {
+	current := testPatchNode(map[string]string{"a": "1"}, nil)
+	current.ResourceVersion = "42"
+	desired := testPatchNode(map[string]string{"a": "2"}, nil)
+
+	patch, err := ComputePatch(current, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `{"metadata":{"labels":{"a":"2"}}}`; string(patch) != expected {
+		t.Fatalf("expected patch %s, got %s", expected, patch)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	clientset "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned"
+	informers "github.com/banzaicloud/nodepool-labels-operator/pkg/client/informers/externalversions"
+	listers "github.com/banzaicloud/nodepool-labels-operator/pkg/client/listers/nodepoollabelset/v1alpha1"
+)
+
+// ChangeType tells whether the npls of a node pool was created, updated or deleted
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "Created"
+	ChangeUpdated ChangeType = "Updated"
+	ChangeDeleted ChangeType = "Deleted"
+)
+
+// Change is a change of the labels of a node pool
+type Change struct {
+	Type     ChangeType
+	Nodepool string
+	// Labels are the labels after the change, nil if the npls was deleted
+	Labels LabelSet
+	// Previous are the labels before the change, nil if the npls was created
+	Previous LabelSet
+}
+
+// CachedManager is a Manager which serves Get and GetAll from an informer
+// cache and notifies subscribers about label changes, writes, Sync and Diff
+// go to the API server
+type CachedManager struct {
+	*Manager
+
+	factory informers.SharedInformerFactory
+	lister  listers.NodePoolLabelSetLister
+
+	mu          sync.RWMutex
+	subscribers map[int]func(Change)
+	nextID      int
+}
+
+// NewCachedManager gives back a CachedManager, its cache is filled by Start
+func NewCachedManager(client clientset.Interface, namespace string, resync time.Duration) *CachedManager {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, resync, informers.WithNamespace(namespace))
+	informer := factory.Labels().V1alpha1().NodePoolLabelSets()
+
+	m := &CachedManager{
+		Manager: NewManager(client, namespace),
+
+		factory: factory,
+		lister:  informer.Lister(),
+
+		subscribers: make(map[int]func(Change)),
+	}
+
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if npls, ok := obj.(*v1alpha1.NodePoolLabelSet); ok {
+				m.notify(Change{Type: ChangeCreated, Nodepool: npls.Name, Labels: copyLabelSet(npls.Spec.Labels)})
+			}
+		},
+		UpdateFunc: func(old, new interface{}) {
+			oldNPLS, ok := old.(*v1alpha1.NodePoolLabelSet)
+			newNPLS, _ := new.(*v1alpha1.NodePoolLabelSet)
+			if !ok || newNPLS == nil || equalLabelSets(oldNPLS.Spec.Labels, newNPLS.Spec.Labels) {
+				return
+			}
+			m.notify(Change{
+				Type:     ChangeUpdated,
+				Nodepool: newNPLS.Name,
+				Labels:   copyLabelSet(newNPLS.Spec.Labels),
+				Previous: copyLabelSet(oldNPLS.Spec.Labels),
+			})
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if npls, ok := obj.(*v1alpha1.NodePoolLabelSet); ok {
+				m.notify(Change{Type: ChangeDeleted, Nodepool: npls.Name, Previous: copyLabelSet(npls.Spec.Labels)})
+			}
+		},
+	})
+
+	return m
+}
+
+// Start starts the informer and waits for the cache to be filled, the
+// informer is stopped when the context is cancelled
+func (m *CachedManager) Start(ctx context.Context) error {
+	m.factory.Start(ctx.Done())
+
+	for informerType, ok := range m.factory.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			return errors.NewWithDetails("could not sync informer cache", "type", informerType.String())
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers a handler which is called on every change of the
+// labels of a node pool, handlers subscribed before Start are also called
+// for the existing ones. The handlers are called one by one from the
+// informer's goroutine, so they should return quickly. The returned function
+// removes the handler.
+func (m *CachedManager) Subscribe(handler func(Change)) func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.nextID
+	m.nextID++
+	m.subscribers[id] = handler
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		delete(m.subscribers, id)
+	}
+}
+
+func (m *CachedManager) Get(ctx context.Context, name string) (LabelSet, error) {
+	npls, err := m.lister.NodePoolLabelSets(m.namespace).Get(name)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "could not get npls from cache", "name", name)
+	}
+
+	return copyLabelSet(npls.Spec.Labels), nil
+}
+
+func (m *CachedManager) GetAll(ctx context.Context) (NodepoolLabelSets, error) {
+	nplss, err := m.lister.NodePoolLabelSets(m.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not list npls resources from cache")
+	}
+
+	sets := make(NodepoolLabelSets)
+	for _, npls := range nplss {
+		sets[npls.Name] = copyLabelSet(npls.Spec.Labels)
+	}
+
+	return sets, nil
+}
+
+func (m *CachedManager) notify(change Change) {
+	m.mu.RLock()
+	handlers := make([]func(Change), 0, len(m.subscribers))
+	for _, handler := range m.subscribers {
+		handlers = append(handlers, handler)
+	}
+	m.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(change)
+	}
+}
+
+// copyLabelSet copies the labels of a cached object, which must not be modified
+func copyLabelSet(labelSet LabelSet) LabelSet {
+	if labelSet == nil {
+		return nil
+	}
+
+	copied := make(LabelSet, len(labelSet))
+	for label, value := range labelSet {
+		copied[label] = value
+	}
+
+	return copied
+}
@@ -0,0 +1,185 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/fake"
+)
+
+// startCachedManager starts a CachedManager on the objects and waits for its
+// watch, the fake clientset drops the events which happen before the watch
+func startCachedManager(t *testing.T, subscribe func(*CachedManager), objects ...runtime.Object) (*CachedManager, *fake.Clientset) {
+	t.Helper()
+
+	client := fake.NewSimpleClientset(objects...)
+	watching := make(chan struct{})
+	client.PrependWatchReactor("nodepoollabelsets", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w, err := client.Tracker().Watch(action.GetResource(), action.GetNamespace())
+		close(watching)
+		return true, w, err
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	m := NewCachedManager(client, testNamespace, 0)
+	subscribe(m)
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("could not start cached manager: %v", err)
+	}
+
+	select {
+	case <-watching:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("informer did not start watching")
+	}
+
+	return m, client
+}
+
+func subscribeChanges(m *CachedManager) (<-chan Change, func()) {
+	changes := make(chan Change, 10)
+	unsubscribe := m.Subscribe(func(change Change) {
+		changes <- change
+	})
+
+	return changes, unsubscribe
+}
+
+func expectChange(t *testing.T, changes <-chan Change, expected Change) {
+	t.Helper()
+
+	select {
+	case change := <-changes:
+		if !reflect.DeepEqual(change, expected) {
+			t.Fatalf("expected change %#v, got %#v", expected, change)
+		}
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatalf("expected change %#v, got none", expected)
+	}
+}
+
+func TestCachedManagerFillsCache(t *testing.T) {
+	var changes <-chan Change
+	m, _ := startCachedManager(t, func(m *CachedManager) {
+		changes, _ = subscribeChanges(m)
+	}, testSet("pool", "", LabelSet{"env": "dev"}), testSet("empty", "", nil))
+
+	received := map[string]Change{}
+	for i := 0; i < 2; i++ {
+		select {
+		case change := <-changes:
+			received[change.Nodepool] = change
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatalf("expected changes of the existing sets, got %v", received)
+		}
+	}
+	expected := map[string]Change{
+		"pool":  {Type: ChangeCreated, Nodepool: "pool", Labels: LabelSet{"env": "dev"}},
+		"empty": {Type: ChangeCreated, Nodepool: "empty"},
+	}
+	if !reflect.DeepEqual(received, expected) {
+		t.Fatalf("expected changes %v, got %v", expected, received)
+	}
+
+	labels, err := m.Get(context.Background(), "pool")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(labels, LabelSet{"env": "dev"}) {
+		t.Fatalf("unexpected labels: %v", labels)
+	}
+
+	sets, err := m.GetAll(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (NodepoolLabelSets{"pool": {"env": "dev"}, "empty": nil}); !reflect.DeepEqual(sets, expected) {
+		t.Fatalf("expected sets %v, got %v", expected, sets)
+	}
+}
+
+func TestCachedManagerNotifiesChanges(t *testing.T) {
+	ctx := context.Background()
+	m, client := startCachedManager(t, func(*CachedManager) {})
+	changes, _ := subscribeChanges(m)
+	nplss := client.LabelsV1alpha1().NodePoolLabelSets(testNamespace)
+
+	npls, err := nplss.Create(ctx, testSet("pool", "", nil), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectChange(t, changes, Change{Type: ChangeCreated, Nodepool: "pool"})
+
+	npls.Spec.Labels = LabelSet{"env": "dev"}
+	if npls, err = nplss.Update(ctx, npls, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectChange(t, changes, Change{Type: ChangeUpdated, Nodepool: "pool", Labels: LabelSet{"env": "dev"}})
+
+	// updates which leave the labels alone are not notified
+	npls.Annotations = map[string]string{"note": "unrelated"}
+	if _, err = nplss.Update(ctx, npls, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = nplss.Delete(ctx, "pool", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectChange(t, changes, Change{Type: ChangeDeleted, Nodepool: "pool", Previous: LabelSet{"env": "dev"}})
+}
+
+func TestCachedManagerUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	m, client := startCachedManager(t, func(*CachedManager) {})
+	unsubscribed, unsubscribe := subscribeChanges(m)
+	subscribed, _ := subscribeChanges(m)
+	nplss := client.LabelsV1alpha1().NodePoolLabelSets(testNamespace)
+
+	if _, err := nplss.Create(ctx, testSet("pool", "", LabelSet{"env": "dev"}), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectChange(t, unsubscribed, Change{Type: ChangeCreated, Nodepool: "pool", Labels: LabelSet{"env": "dev"}})
+	expectChange(t, subscribed, Change{Type: ChangeCreated, Nodepool: "pool", Labels: LabelSet{"env": "dev"}})
+
+	unsubscribe()
+
+	if err := nplss.Delete(ctx, "pool", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := nplss.Create(ctx, testSet("other", "", nil), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectChange(t, subscribed, Change{Type: ChangeDeleted, Nodepool: "pool", Previous: LabelSet{"env": "dev"}})
+	// the handlers of a change are called before the next change is notified
+	expectChange(t, subscribed, Change{Type: ChangeCreated, Nodepool: "other"})
+
+	select {
+	case change := <-unsubscribed:
+		t.Fatalf("unexpected change after unsubscribe: %#v", change)
+	default:
+	}
+}
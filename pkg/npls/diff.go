@@ -0,0 +1,113 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"context"
+
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// DiffOptions configures Diff
+type DiffOptions struct {
+	// SyncOptions are the options of the planned Sync
+	SyncOptions
+	// Nodes are used to count the nodes affected by the changes, if set
+	Nodes []api_v1.Node
+	// NodepoolNameLabels are the node labels the node pool of a node is
+	// determined from, DefaultNodepoolNameLabels if empty
+	NodepoolNameLabels []string
+}
+
+// LabelChange is the current and the desired value of a label
+type LabelChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// NodepoolDiff is the change Sync would make to the labels of a node pool
+type NodepoolDiff struct {
+	Added   LabelSet               `json:"added"`
+	Changed map[string]LabelChange `json:"changed"`
+	// Removed holds the current values of the removed labels
+	Removed LabelSet `json:"removed"`
+	// AffectedNodes is the number of nodes of the node pool, zero if no nodes were given
+	AffectedNodes int `json:"affectedNodes"`
+}
+
+// Diff gives back the label changes Sync would make with the same sets and
+// options by node pool, node pools without label changes are left out
+func (m *Manager) Diff(ctx context.Context, sets NodepoolLabelSets, options DiffOptions) (map[string]NodepoolDiff, error) {
+	current, err := m.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodepoolNameLabels := options.NodepoolNameLabels
+	if len(nodepoolNameLabels) == 0 {
+		nodepoolNameLabels = DefaultNodepoolNameLabels
+	}
+	nodeCounts := make(map[string]int)
+	for i := range options.Nodes {
+		nodeCounts[NodepoolName(&options.Nodes[i], nodepoolNameLabels)]++
+	}
+
+	diffs := make(map[string]NodepoolDiff)
+	for _, poolName := range m.syncedPools(sets, current, options.SyncOptions) {
+		var currentLabels LabelSet
+		if npls, ok := current[poolName]; ok {
+			// Sync refuses to write npls resources managed by another system
+			if !m.isWritable(npls.ObjectMeta, options.Adopt) {
+				continue
+			}
+			currentLabels = npls.Spec.Labels
+		}
+
+		diff := diffLabelSets(currentLabels, sets[poolName])
+		if len(diff.Added) == 0 && len(diff.Changed) == 0 && len(diff.Removed) == 0 {
+			continue
+		}
+		diff.AffectedNodes = nodeCounts[poolName]
+		diffs[poolName] = diff
+	}
+
+	return diffs, nil
+}
+
+func diffLabelSets(current, desired LabelSet) NodepoolDiff {
+	diff := NodepoolDiff{
+		Added:   make(LabelSet),
+		Changed: make(map[string]LabelChange),
+		Removed: make(LabelSet),
+	}
+
+	for label, value := range desired {
+		currentValue, ok := current[label]
+		switch {
+		case !ok:
+			diff.Added[label] = value
+		case currentValue != value:
+			diff.Changed[label] = LabelChange{From: currentValue, To: value}
+		}
+	}
+
+	for label, value := range current {
+		if _, ok := desired[label]; !ok {
+			diff.Removed[label] = value
+		}
+	}
+
+	return diff
+}
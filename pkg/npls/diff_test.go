@@ -0,0 +1,171 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	api_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/fake"
+)
+
+func testNode(name string, labels map[string]string) api_v1.Node {
+	return api_v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	nodes := []api_v1.Node{
+		testNode("node-1", map[string]string{DefaultNodepoolNameLabels[0]: "pool"}),
+		testNode("node-2", map[string]string{DefaultNodepoolNameLabels[0]: "pool"}),
+		testNode("node-3", map[string]string{"custom/pool": "pool"}),
+		testNode("node-4", map[string]string{DefaultNodepoolNameLabels[0]: "other"}),
+	}
+
+	tests := []struct {
+		name     string
+		existing []runtime.Object
+		sets     NodepoolLabelSets
+		options  DiffOptions
+		diffs    map[string]NodepoolDiff
+	}{
+		{
+			name:     "added, changed and removed labels",
+			existing: []runtime.Object{testSet("pool", DefaultManagedBy, LabelSet{"env": "dev", "team": "a", "old": "x"})},
+			sets:     NodepoolLabelSets{"pool": {"env": "prod", "team": "a", "new": "y"}},
+			diffs: map[string]NodepoolDiff{
+				"pool": {
+					Added:   LabelSet{"new": "y"},
+					Changed: map[string]LabelChange{"env": {From: "dev", To: "prod"}},
+					Removed: LabelSet{"old": "x"},
+				},
+			},
+		},
+		{
+			name:     "node pools without changes are left out",
+			existing: []runtime.Object{testSet("pool", DefaultManagedBy, LabelSet{"env": "prod"})},
+			sets:     NodepoolLabelSets{"pool": {"env": "prod"}},
+			diffs:    map[string]NodepoolDiff{},
+		},
+		{
+			name:     "affected nodes are counted",
+			existing: []runtime.Object{testSet("pool", DefaultManagedBy, LabelSet{"env": "dev"})},
+			sets:     NodepoolLabelSets{"pool": {"env": "prod"}, "new": {"env": "prod"}},
+			options:  DiffOptions{Nodes: nodes},
+			diffs: map[string]NodepoolDiff{
+				"pool": {
+					Changed:       map[string]LabelChange{"env": {From: "dev", To: "prod"}},
+					AffectedNodes: 2,
+				},
+				"new": {
+					Added: LabelSet{"env": "prod"},
+				},
+			},
+		},
+		{
+			name:     "node pools are determined by the given labels",
+			existing: []runtime.Object{testSet("pool", DefaultManagedBy, LabelSet{"env": "dev"})},
+			sets:     NodepoolLabelSets{"pool": {"env": "prod"}},
+			options:  DiffOptions{Nodes: nodes, NodepoolNameLabels: []string{"custom/pool"}},
+			diffs: map[string]NodepoolDiff{
+				"pool": {
+					Changed:       map[string]LabelChange{"env": {From: "dev", To: "prod"}},
+					AffectedNodes: 1,
+				},
+			},
+		},
+		{
+			name: "sets of other systems are left out unless adopted",
+			existing: []runtime.Object{
+				testSet("pool", testOtherManager, LabelSet{"env": "dev"}),
+				testSet("deleted", testOtherManager, LabelSet{"env": "dev"}),
+				testSet("unlabeled", "", LabelSet{"env": "dev"}),
+			},
+			sets: NodepoolLabelSets{"pool": {"env": "prod"}, "deleted": {}, "unlabeled": {"env": "prod"}},
+			diffs: map[string]NodepoolDiff{
+				"unlabeled": {
+					Changed: map[string]LabelChange{"env": {From: "dev", To: "prod"}},
+				},
+			},
+		},
+		{
+			name:     "adopted sets are diffed",
+			existing: []runtime.Object{testSet("pool", testOtherManager, LabelSet{"env": "dev"})},
+			sets:     NodepoolLabelSets{"pool": {"env": "prod"}},
+			options:  DiffOptions{SyncOptions: SyncOptions{WriteOptions: WriteOptions{Adopt: true}}},
+			diffs: map[string]NodepoolDiff{
+				"pool": {
+					Changed: map[string]LabelChange{"env": {From: "dev", To: "prod"}},
+				},
+			},
+		},
+		{
+			name: "pruned node pools lose every label",
+			existing: []runtime.Object{
+				testSet("managed", DefaultManagedBy, LabelSet{"env": "prod"}),
+				testSet("unlabeled", "", LabelSet{"env": "prod"}),
+			},
+			sets:    NodepoolLabelSets{},
+			options: DiffOptions{SyncOptions: SyncOptions{Prune: true}},
+			diffs: map[string]NodepoolDiff{
+				"managed": {
+					Removed: LabelSet{"env": "prod"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(test.existing...)
+			m := NewManager(client, testNamespace)
+
+			diffs, err := m.Diff(context.Background(), test.sets, test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for poolName, diff := range test.diffs {
+				if diff.Added == nil {
+					diff.Added = LabelSet{}
+				}
+				if diff.Changed == nil {
+					diff.Changed = map[string]LabelChange{}
+				}
+				if diff.Removed == nil {
+					diff.Removed = LabelSet{}
+				}
+				test.diffs[poolName] = diff
+			}
+			if !reflect.DeepEqual(diffs, test.diffs) {
+				t.Errorf("expected diffs %+v, got %+v", test.diffs, diffs)
+			}
+
+			if len(client.Actions()) != 1 {
+				t.Errorf("expected Diff to only list the sets, got %v", client.Actions())
+			}
+		})
+	}
+}
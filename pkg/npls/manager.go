@@ -1,27 +1,43 @@
 package npls
 
 import (
+	"context"
+
 	"emperror.dev/errors"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
 
 	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
 	clientset "github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned"
 )
 
+const (
+	// ManagedByLabel marks the npls resources written by the Manager
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	// DefaultManagedBy is the value of the ManagedByLabel if none is set
+	DefaultManagedBy = "nodepool-labels-manager"
+)
+
+// ErrNotManaged is returned when writing an npls labeled as managed by
+// another system without adopting it
+const ErrNotManaged = errors.Sentinel("npls is not managed by the manager")
+
 type LabelSet map[string]string
 type NodepoolLabelSets map[string]LabelSet
 
 type Manager struct {
 	namespace string
+	managedBy string
 	clientset clientset.Interface
 }
 
 func NewManager(client clientset.Interface, namespace string) *Manager {
 	return &Manager{
 		namespace: namespace,
+		managedBy: DefaultManagedBy,
 		clientset: client,
 	}
 }
@@ -32,14 +48,17 @@ func NewNPLSManager(k8sConfig *rest.Config, namespace string) (*Manager, error)
 		return nil, errors.WrapIf(err, "could not get k8s npls clientset")
 	}
 
-	return &Manager{
-		namespace: namespace,
-		clientset: clientset,
-	}, nil
+	return NewManager(clientset, namespace), nil
+}
+
+// SetManagedBy sets the value of the ManagedByLabel which identifies the
+// system writing the npls resources, it must be set before the Manager is used
+func (m *Manager) SetManagedBy(managedBy string) {
+	m.managedBy = managedBy
 }
 
-func (m *Manager) Get(name string) (LabelSet, error) {
-	npls, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Get(name, v1.GetOptions{})
+func (m *Manager) Get(ctx context.Context, name string) (LabelSet, error) {
+	npls, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Get(ctx, name, v1.GetOptions{})
 	if err != nil {
 		return nil, errors.WrapIfWithDetails(err, "could not get npls", "name", name)
 	}
@@ -47,8 +66,8 @@ func (m *Manager) Get(name string) (LabelSet, error) {
 	return LabelSet(npls.Spec.Labels), nil
 }
 
-func (m *Manager) GetAll() (NodepoolLabelSets, error) {
-	nplss, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).List(v1.ListOptions{})
+func (m *Manager) GetAll(ctx context.Context) (NodepoolLabelSets, error) {
+	nplss, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).List(ctx, v1.ListOptions{})
 	if err != nil {
 		return nil, errors.WrapIf(err, "could not list npls resources")
 	}
@@ -61,80 +80,132 @@ func (m *Manager) GetAll() (NodepoolLabelSets, error) {
 	return sets, nil
 }
 
-func (m *Manager) Sync(sets NodepoolLabelSets) error {
-	errs := make([]error, 0, len(sets))
-
-	for poolName, labelSet := range sets {
-		if len(labelSet) == 0 {
-			err := m.Delete(poolName)
-			if err != nil {
-				errs = append(errs, err)
-			}
-			continue
-		}
-		err := m.UpdateOrCreate(poolName, labelSet)
-		if err != nil {
-			errs = append(errs, err)
-		}
-	}
+// WriteOptions configures the writes of the npls resources
+type WriteOptions struct {
+	// Metadata is added to the written npls resources
+	Metadata Metadata
+	// Adopt allows writing npls resources labeled as managed by another
+	// system, they are labeled as managed by the Manager from then on
+	Adopt bool
+}
 
-	return errors.Combine(errs...)
+func (m *Manager) UpdateOrCreate(ctx context.Context, name string, labelSet LabelSet) error {
+	return m.UpdateOrCreateWithOptions(ctx, name, labelSet, WriteOptions{})
 }
 
-func (m *Manager) UpdateOrCreate(name string, labelSet LabelSet) error {
-	err := m.Update(name, labelSet)
-	if err != nil && k8serrors.IsNotFound(errors.Cause(err)) {
-		err = m.Create(name, labelSet)
-		if err != nil {
-			return err
-		}
-	} else if err != nil {
-		return err
-	}
+// UpdateOrCreateWithOptions updates an npls, or creates it if it doesn't exist
+func (m *Manager) UpdateOrCreateWithOptions(ctx context.Context, name string, labelSet LabelSet, options WriteOptions) error {
+	_, err := m.updateOrCreate(ctx, name, labelSet, options)
 
-	return nil
+	return err
 }
 
-func (m *Manager) Update(name string, labelSet LabelSet) error {
-	npls, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Get(name, v1.GetOptions{})
-	if err != nil {
-		return errors.WrapIfWithDetails(err, "could not get npls", "name", name)
+// updateOrCreate works like UpdateOrCreateWithOptions and tells whether the npls was created
+func (m *Manager) updateOrCreate(ctx context.Context, name string, labelSet LabelSet, options WriteOptions) (bool, error) {
+	err := m.UpdateWithOptions(ctx, name, labelSet, options)
+	if err == nil || !k8serrors.IsNotFound(errors.Cause(err)) {
+		return false, err
 	}
 
-	npls.Spec.Labels = labelSet
-	_, err = m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Update(npls)
+	err = m.CreateWithOptions(ctx, name, labelSet, options)
+	// the npls was created concurrently, update it instead
+	if err != nil && k8serrors.IsAlreadyExists(errors.Cause(err)) {
+		return false, m.UpdateWithOptions(ctx, name, labelSet, options)
+	}
 	if err != nil {
-		return errors.WrapIfWithDetails(err, "could not update npls", "name", name)
+		return false, err
 	}
 
-	return nil
+	return true, nil
+}
+
+// Update replaces the labels of an npls, the update is retried on a fresh
+// copy if the npls was modified concurrently
+func (m *Manager) Update(ctx context.Context, name string, labelSet LabelSet) error {
+	return m.UpdateWithOptions(ctx, name, labelSet, WriteOptions{})
+}
+
+// UpdateWithOptions works like Update and adds the metadata of the options to
+// the npls, npls resources managed by another system are only updated if the
+// options allow adopting them, otherwise ErrNotManaged is returned
+func (m *Manager) UpdateWithOptions(ctx context.Context, name string, labelSet LabelSet, options WriteOptions) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		npls, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "could not get npls", "name", name)
+		}
+
+		if !m.isWritable(npls.ObjectMeta, options.Adopt) {
+			return notManagedError(npls.ObjectMeta)
+		}
+
+		npls.Spec.Labels = labelSet
+		options.Metadata.apply(&npls.ObjectMeta, m.managedBy)
+		_, err = m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Update(ctx, npls, v1.UpdateOptions{})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "could not update npls", "name", name)
+		}
+
+		return nil
+	})
+}
+
+// Delete deletes an npls, npls resources managed by another system are not
+// deleted and ErrNotManaged is returned
+func (m *Manager) Delete(ctx context.Context, name string) error {
+	return m.delete(ctx, name, false)
 }
 
-func (m *Manager) Delete(name string) error {
-	err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Delete(name, &v1.DeleteOptions{})
-	if k8serrors.IsNotFound(err) {
+func (m *Manager) delete(ctx context.Context, name string, adopt bool) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		npls, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "could not get npls", "name", name)
+		}
+
+		if !m.isWritable(npls.ObjectMeta, adopt) {
+			return notManagedError(npls.ObjectMeta)
+		}
+
+		// the npls is only deleted if it wasn't changed since the check
+		err = m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Delete(ctx, name, v1.DeleteOptions{
+			Preconditions: &v1.Preconditions{
+				UID:             &npls.UID,
+				ResourceVersion: &npls.ResourceVersion,
+			},
+		})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "could not delete npls", "name", name)
+		}
+
+		return nil
+	})
+	if k8serrors.IsNotFound(errors.Cause(err)) {
 		return nil
 	}
 
-	if err != nil {
-		return errors.WrapIfWithDetails(err, "could not delete npls", "name", name)
-	}
+	return err
+}
 
-	return nil
+// Create creates an npls with the given labels
+func (m *Manager) Create(ctx context.Context, name string, labelSet LabelSet) error {
+	return m.CreateWithOptions(ctx, name, labelSet, WriteOptions{})
 }
 
-func (m *Manager) Create(name string, labelSet LabelSet) error {
-	_, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Create(
-		&v1alpha1.NodePoolLabelSet{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      name,
-				Namespace: m.namespace,
-			},
-			Spec: v1alpha1.NodePoolLabelSetSpec{
-				Labels: labelSet,
-			},
+// CreateWithOptions works like Create and adds the metadata of the options to the npls
+func (m *Manager) CreateWithOptions(ctx context.Context, name string, labelSet LabelSet, options WriteOptions) error {
+	npls := &v1alpha1.NodePoolLabelSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: m.namespace,
+		},
+		Spec: v1alpha1.NodePoolLabelSetSpec{
+			Labels: labelSet,
 		},
-	)
+	}
+	options.Metadata.apply(&npls.ObjectMeta, m.managedBy)
+
+	_, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Create(ctx, npls, v1.CreateOptions{})
 
 	if err != nil {
 		return errors.WrapIfWithDetails(err, "could not create npls", "name", name)
@@ -142,3 +213,20 @@ func (m *Manager) Create(name string, labelSet LabelSet) error {
 
 	return nil
 }
+
+func (m *Manager) isManaged(meta metav1.ObjectMeta) bool {
+	return meta.Labels[ManagedByLabel] == m.managedBy
+}
+
+// isWritable tells whether the Manager may write an npls, npls resources
+// without the ManagedByLabel were written before the label was introduced
+// and are adopted, the ones managed by another system only if adopt is set
+func (m *Manager) isWritable(meta metav1.ObjectMeta, adopt bool) bool {
+	managedBy, ok := meta.Labels[ManagedByLabel]
+
+	return adopt || !ok || managedBy == m.managedBy
+}
+
+func notManagedError(meta metav1.ObjectMeta) error {
+	return errors.WithDetails(ErrNotManaged, "name", meta.Name, "managedBy", meta.Labels[ManagedByLabel])
+}
@@ -0,0 +1,153 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"emperror.dev/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/fake"
+)
+
+// testWrite is a write of the "pool" npls and the labels it leaves on it
+type testWrite struct {
+	name    string
+	write   func(ctx context.Context, m *Manager) error
+	labels  LabelSet
+	deleted bool
+}
+
+var testWrites = []testWrite{
+	{
+		name: "Update",
+		write: func(ctx context.Context, m *Manager) error {
+			return m.Update(ctx, "pool", LabelSet{"env": "prod"})
+		},
+		labels: LabelSet{"env": "prod"},
+	},
+	{
+		name: "UpdateOrCreate",
+		write: func(ctx context.Context, m *Manager) error {
+			return m.UpdateOrCreate(ctx, "pool", LabelSet{"env": "prod"})
+		},
+		labels: LabelSet{"env": "prod"},
+	},
+	{
+		name: "AddLabels",
+		write: func(ctx context.Context, m *Manager) error {
+			return m.AddLabels(ctx, "pool", LabelSet{"team": "a"})
+		},
+		labels: LabelSet{"env": "dev", "team": "a"},
+	},
+	{
+		name: "RemoveLabels",
+		write: func(ctx context.Context, m *Manager) error {
+			return m.RemoveLabels(ctx, "pool", "env")
+		},
+		labels: LabelSet{},
+	},
+	{
+		name: "Delete",
+		write: func(ctx context.Context, m *Manager) error {
+			return m.Delete(ctx, "pool")
+		},
+		deleted: true,
+	},
+}
+
+func TestWritesAdoptUnlabeledSets(t *testing.T) {
+	for _, test := range testWrites {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := fake.NewSimpleClientset(testSet("pool", "", LabelSet{"env": "dev"}))
+			m := NewManager(client, testNamespace)
+
+			if err := test.write(ctx, m); err != nil {
+				t.Fatal(err)
+			}
+
+			npls, err := client.LabelsV1alpha1().NodePoolLabelSets(testNamespace).Get(ctx, "pool", metav1.GetOptions{})
+			if test.deleted {
+				if !k8serrors.IsNotFound(err) {
+					t.Fatalf("expected the npls to be deleted, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if npls.Labels[ManagedByLabel] != DefaultManagedBy {
+				t.Errorf("expected the npls to be labeled as managed, got labels %v", npls.Labels)
+			}
+			if !reflect.DeepEqual(LabelSet(npls.Spec.Labels), test.labels) {
+				t.Errorf("expected labels %v, got %v", test.labels, npls.Spec.Labels)
+			}
+		})
+	}
+}
+
+func TestWritesRefuseSetsOfOtherSystems(t *testing.T) {
+	writes := append(testWrites, testWrite{
+		name: "Apply",
+		write: func(ctx context.Context, m *Manager) error {
+			return m.Apply(ctx, "pool", LabelSet{"env": "prod"}, ApplyOptions{})
+		},
+	})
+
+	for _, test := range writes {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(testSet("pool", testOtherManager, LabelSet{"env": "dev"}))
+			m := NewManager(client, testNamespace)
+
+			err := test.write(context.Background(), m)
+			if !errors.Is(err, ErrNotManaged) {
+				t.Fatalf("expected ErrNotManaged, got %v", err)
+			}
+
+			for _, action := range client.Actions() {
+				if action.GetVerb() != "get" {
+					t.Errorf("expected the npls to be left intact, got %v", action)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateWithAdoptTakesOverSetsOfOtherSystems(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(testSet("pool", testOtherManager, LabelSet{"env": "dev"}))
+	m := NewManager(client, testNamespace)
+
+	err := m.UpdateWithOptions(ctx, "pool", LabelSet{"env": "prod"}, WriteOptions{Adopt: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	npls, err := client.LabelsV1alpha1().NodePoolLabelSets(testNamespace).Get(ctx, "pool", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if npls.Labels[ManagedByLabel] != DefaultManagedBy {
+		t.Errorf("expected the npls to be labeled as managed, got labels %v", npls.Labels)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	applymetav1 "k8s.io/client-go/applyconfigurations/meta/v1"
+)
+
+// Metadata is added to the npls resources written by the Manager, along
+// with the ManagedByLabel
+type Metadata struct {
+	Labels      map[string]string
+	Annotations map[string]string
+	// OwnerReferences make the npls resources garbage collected with their owners
+	OwnerReferences []metav1.OwnerReference
+}
+
+// apply adds the metadata to the metadata of an npls, other labels,
+// annotations and owner references of the npls are kept
+func (md Metadata) apply(meta *metav1.ObjectMeta, managedBy string) {
+	if meta.Labels == nil {
+		meta.Labels = make(map[string]string)
+	}
+	for label, value := range md.Labels {
+		meta.Labels[label] = value
+	}
+	meta.Labels[ManagedByLabel] = managedBy
+
+	if len(md.Annotations) > 0 && meta.Annotations == nil {
+		meta.Annotations = make(map[string]string)
+	}
+	for annotation, value := range md.Annotations {
+		meta.Annotations[annotation] = value
+	}
+
+	for _, ref := range md.OwnerReferences {
+		if i := ownerReferenceIndex(meta.OwnerReferences, ref); i >= 0 {
+			meta.OwnerReferences[i] = ref
+			continue
+		}
+		meta.OwnerReferences = append(meta.OwnerReferences, ref)
+	}
+}
+
+// isApplied tells whether the metadata of an npls already contains the metadata
+func (md Metadata) isApplied(meta metav1.ObjectMeta, managedBy string) bool {
+	if meta.Labels[ManagedByLabel] != managedBy {
+		return false
+	}
+
+	for label, value := range md.Labels {
+		if v, ok := meta.Labels[label]; !ok || v != value {
+			return false
+		}
+	}
+
+	for annotation, value := range md.Annotations {
+		if v, ok := meta.Annotations[annotation]; !ok || v != value {
+			return false
+		}
+	}
+
+	for _, ref := range md.OwnerReferences {
+		i := ownerReferenceIndex(meta.OwnerReferences, ref)
+		if i < 0 || !reflect.DeepEqual(meta.OwnerReferences[i], ref) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ownerReferenceApplyConfigurations gives back the owner references as apply configurations
+func (md Metadata) ownerReferenceApplyConfigurations() []*applymetav1.OwnerReferenceApplyConfiguration {
+	refs := make([]*applymetav1.OwnerReferenceApplyConfiguration, 0, len(md.OwnerReferences))
+	for _, ref := range md.OwnerReferences {
+		refConfig := applymetav1.OwnerReference().
+			WithAPIVersion(ref.APIVersion).
+			WithKind(ref.Kind).
+			WithName(ref.Name).
+			WithUID(ref.UID)
+		if ref.Controller != nil {
+			refConfig.WithController(*ref.Controller)
+		}
+		if ref.BlockOwnerDeletion != nil {
+			refConfig.WithBlockOwnerDeletion(*ref.BlockOwnerDeletion)
+		}
+		refs = append(refs, refConfig)
+	}
+
+	return refs
+}
+
+func ownerReferenceIndex(refs []metav1.OwnerReference, ref metav1.OwnerReference) int {
+	for i := range refs {
+		if refs[i].UID == ref.UID {
+			return i
+		}
+	}
+
+	return -1
+}
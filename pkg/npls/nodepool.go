@@ -0,0 +1,41 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	api_v1 "k8s.io/api/core/v1"
+)
+
+// DefaultNodepoolNameLabels are the node labels the node pool of a node is
+// determined from if none are given, same as the operator's default
+var DefaultNodepoolNameLabels = []string{
+	"nodepool.banzaicloud.io/name",
+	"cloud.google.com/gke-nodepool",
+	"agentpool",
+}
+
+// NodepoolName gives back the name of the node pool a node belongs to, which
+// is the value of the first non-empty nodepoolNameLabels label of the node
+func NodepoolName(node *api_v1.Node, nodepoolNameLabels []string) string {
+	labels := node.GetLabels()
+
+	for _, label := range nodepoolNameLabels {
+		if labels[label] != "" {
+			return labels[label]
+		}
+	}
+
+	return ""
+}
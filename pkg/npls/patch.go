@@ -0,0 +1,145 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"context"
+	"encoding/json"
+
+	"emperror.dev/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	applyv1alpha1 "github.com/banzaicloud/nodepool-labels-operator/pkg/client/applyconfiguration/nodepoollabelset/v1alpha1"
+)
+
+// DefaultFieldManager is the field manager of server-side applies if none is set
+const DefaultFieldManager = "nodepool-labels-manager"
+
+// ApplyOptions configures a server-side apply of a label set
+type ApplyOptions struct {
+	// FieldManager identifies the owner of the applied labels, defaults to DefaultFieldManager
+	FieldManager string
+	// Force takes over labels owned by other field managers instead of failing with a conflict
+	Force bool
+	// Metadata is applied along with the labels, the labels, annotations and
+	// owner references are owned by the field manager
+	Metadata Metadata
+	// Adopt allows applying to an npls managed by another system, otherwise
+	// ErrNotManaged is returned
+	Adopt bool
+}
+
+// AddLabels adds labels to an npls or changes their values, the other labels
+// are left intact. Custom resources don't support strategic merge patches,
+// so a JSON merge patch is used, conditional on the resource version of the
+// npls checked not to be managed by another system.
+func (m *Manager) AddLabels(ctx context.Context, name string, labelSet LabelSet) error {
+	labels := make(map[string]interface{}, len(labelSet))
+	for label, value := range labelSet {
+		labels[label] = value
+	}
+
+	return m.patchLabels(ctx, name, labels)
+}
+
+// RemoveLabels removes labels from an npls, the other labels are left intact
+func (m *Manager) RemoveLabels(ctx context.Context, name string, labels ...string) error {
+	removed := make(map[string]interface{}, len(labels))
+	for _, label := range labels {
+		removed[label] = nil
+	}
+
+	return m.patchLabels(ctx, name, removed)
+}
+
+// Apply applies the labels of an npls with server-side apply, the npls is
+// created if it doesn't exist. Labels applied earlier by the same field
+// manager and missing from the label set are removed, labels owned by other
+// field managers are left intact.
+func (m *Manager) Apply(ctx context.Context, name string, labelSet LabelSet, options ApplyOptions) error {
+	fieldManager := options.FieldManager
+	if fieldManager == "" {
+		fieldManager = DefaultFieldManager
+	}
+
+	if !options.Adopt {
+		current, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return errors.WrapIfWithDetails(err, "could not get npls", "name", name)
+		}
+		if err == nil && !m.isWritable(current.ObjectMeta, false) {
+			return notManagedError(current.ObjectMeta)
+		}
+	}
+
+	npls := applyv1alpha1.NodePoolLabelSet(name, m.namespace).
+		WithLabels(options.Metadata.Labels).
+		WithLabels(map[string]string{ManagedByLabel: m.managedBy}).
+		WithAnnotations(options.Metadata.Annotations).
+		WithOwnerReferences(options.Metadata.ownerReferenceApplyConfigurations()...).
+		WithSpec(applyv1alpha1.NodePoolLabelSetSpec().WithLabels(labelSet))
+
+	_, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Apply(ctx, npls, v1.ApplyOptions{
+		FieldManager: fieldManager,
+		Force:        options.Force,
+	})
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not apply npls", "name", name, "fieldManager", fieldManager)
+	}
+
+	return nil
+}
+
+func (m *Manager) patchLabels(ctx context.Context, name string, labels map[string]interface{}) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		npls, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "could not get npls", "name", name)
+		}
+
+		if !m.isWritable(npls.ObjectMeta, false) {
+			return notManagedError(npls.ObjectMeta)
+		}
+
+		patch, err := json.Marshal(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]interface{}{
+					ManagedByLabel: m.managedBy,
+				},
+				"resourceVersion": npls.ResourceVersion,
+			},
+			"spec": map[string]interface{}{
+				"labels": labels,
+			},
+		})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "could not marshal npls patch", "name", name)
+		}
+
+		_, err = m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).Patch(ctx, name, types.MergePatchType, patch, v1.PatchOptions{})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "could not patch npls", "name", name)
+		}
+
+		return nil
+	})
+}
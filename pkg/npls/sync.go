@@ -0,0 +1,154 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"context"
+	"sort"
+
+	"emperror.dev/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+)
+
+// SyncOptions configures Sync
+type SyncOptions struct {
+	// Prune deletes the npls resources of the node pools missing from the
+	// synced sets, only the ones written by the Manager are deleted
+	Prune bool
+	// WriteOptions configure the written npls resources, npls resources
+	// managed by another system are only updated or deleted if adopted
+	WriteOptions
+}
+
+// SyncResult lists the node pools by the change made to their npls resource
+type SyncResult struct {
+	Created   []string `json:"created"`
+	Updated   []string `json:"updated"`
+	Deleted   []string `json:"deleted"`
+	Unchanged []string `json:"unchanged"`
+}
+
+// Sync makes the npls resources match the given sets, the npls of a node
+// pool with an empty label set is deleted. The result lists the node pools
+// synced successfully, the failures are returned as a combined error.
+func (m *Manager) Sync(ctx context.Context, sets NodepoolLabelSets, options SyncOptions) (*SyncResult, error) {
+	current, err := m.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	poolNames := m.syncedPools(sets, current, options)
+
+	result := &SyncResult{
+		Created:   make([]string, 0),
+		Updated:   make([]string, 0),
+		Deleted:   make([]string, 0),
+		Unchanged: make([]string, 0),
+	}
+	errs := make([]error, 0)
+
+	for _, poolName := range poolNames {
+		if ctx.Err() != nil {
+			errs = append(errs, errors.WrapIf(ctx.Err(), "could not sync npls resources"))
+			break
+		}
+
+		var err error
+		labelSet := sets[poolName]
+		npls, exists := current[poolName]
+
+		switch {
+		case len(labelSet) == 0 && !exists:
+			continue
+		case exists && !m.isWritable(npls.ObjectMeta, options.Adopt):
+			err = notManagedError(npls.ObjectMeta)
+		case len(labelSet) == 0:
+			err = m.delete(ctx, poolName, options.Adopt)
+			if err == nil {
+				result.Deleted = append(result.Deleted, poolName)
+			}
+		case exists && options.Metadata.isApplied(npls.ObjectMeta, m.managedBy) && equalLabelSets(npls.Spec.Labels, labelSet):
+			result.Unchanged = append(result.Unchanged, poolName)
+		default:
+			// the listed npls resources may be stale, so the result tells
+			// whether the npls was actually created or updated
+			var created bool
+			created, err = m.updateOrCreate(ctx, poolName, labelSet, options.WriteOptions)
+			switch {
+			case err != nil:
+			case created:
+				result.Created = append(result.Created, poolName)
+			default:
+				result.Updated = append(result.Updated, poolName)
+			}
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return result, errors.Combine(errs...)
+}
+
+// list gives back the npls resources by node pool name
+func (m *Manager) list(ctx context.Context) (map[string]*v1alpha1.NodePoolLabelSet, error) {
+	nplss, err := m.clientset.LabelsV1alpha1().NodePoolLabelSets(m.namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not list npls resources")
+	}
+
+	current := make(map[string]*v1alpha1.NodePoolLabelSet, len(nplss.Items))
+	for i := range nplss.Items {
+		current[nplss.Items[i].Name] = &nplss.Items[i]
+	}
+
+	return current, nil
+}
+
+// syncedPools gives back the sorted names of the node pools touched by a
+// sync, including the ones to be pruned
+func (m *Manager) syncedPools(sets NodepoolLabelSets, current map[string]*v1alpha1.NodePoolLabelSet, options SyncOptions) []string {
+	poolNames := make([]string, 0, len(sets))
+	for poolName := range sets {
+		poolNames = append(poolNames, poolName)
+	}
+	if options.Prune {
+		for poolName, npls := range current {
+			if _, ok := sets[poolName]; !ok && m.isManaged(npls.ObjectMeta) {
+				poolNames = append(poolNames, poolName)
+			}
+		}
+	}
+	sort.Strings(poolNames)
+
+	return poolNames
+}
+
+func equalLabelSets(a, b LabelSet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for label, value := range a {
+		if bv, ok := b[label]; !ok || bv != value {
+			return false
+		}
+	}
+
+	return true
+}
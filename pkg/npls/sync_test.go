@@ -0,0 +1,256 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package npls
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"emperror.dev/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/client/clientset/versioned/fake"
+)
+
+const (
+	testNamespace = "default"
+	// testOtherManager manages the npls resources of another system
+	testOtherManager = "other-system"
+)
+
+// testSet gives back an npls labeled as managed by managedBy, unlabeled if empty
+func testSet(name string, managedBy string, labels LabelSet) *v1alpha1.NodePoolLabelSet {
+	npls := &v1alpha1.NodePoolLabelSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Spec: v1alpha1.NodePoolLabelSetSpec{
+			Labels: labels,
+		},
+	}
+	if managedBy != "" {
+		npls.Labels = map[string]string{ManagedByLabel: managedBy}
+	}
+
+	return npls
+}
+
+func TestSync(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []runtime.Object
+		sets     NodepoolLabelSets
+		options  SyncOptions
+		// staleList makes listing give back no npls resources
+		staleList bool
+
+		result    SyncResult
+		failed    bool
+		remaining NodepoolLabelSets
+		managed   []string
+	}{
+		{
+			name: "missing sets are created",
+			sets: NodepoolLabelSets{"pool": {"env": "prod"}},
+			result: SyncResult{
+				Created: []string{"pool"},
+			},
+			remaining: NodepoolLabelSets{"pool": {"env": "prod"}},
+		},
+		{
+			name:     "changed sets are updated",
+			existing: []runtime.Object{testSet("pool", DefaultManagedBy, LabelSet{"env": "dev"})},
+			sets:     NodepoolLabelSets{"pool": {"env": "prod"}},
+			result: SyncResult{
+				Updated: []string{"pool"},
+			},
+			remaining: NodepoolLabelSets{"pool": {"env": "prod"}},
+		},
+		{
+			name:     "equal managed sets are unchanged",
+			existing: []runtime.Object{testSet("pool", DefaultManagedBy, LabelSet{"env": "prod"})},
+			sets:     NodepoolLabelSets{"pool": {"env": "prod"}},
+			result: SyncResult{
+				Unchanged: []string{"pool"},
+			},
+			remaining: NodepoolLabelSets{"pool": {"env": "prod"}},
+		},
+		{
+			name:     "empty sets are deleted",
+			existing: []runtime.Object{testSet("pool", DefaultManagedBy, LabelSet{"env": "prod"})},
+			sets:     NodepoolLabelSets{"pool": {}, "missing": {}},
+			result: SyncResult{
+				Deleted: []string{"pool"},
+			},
+			remaining: NodepoolLabelSets{},
+		},
+		{
+			name: "only managed sets are pruned",
+			existing: []runtime.Object{
+				testSet("managed", DefaultManagedBy, LabelSet{"env": "prod"}),
+				testSet("unlabeled", "", LabelSet{"env": "prod"}),
+				testSet("other", testOtherManager, LabelSet{"env": "prod"}),
+			},
+			sets:    NodepoolLabelSets{},
+			options: SyncOptions{Prune: true},
+			result: SyncResult{
+				Deleted: []string{"managed"},
+			},
+			remaining: NodepoolLabelSets{"unlabeled": {"env": "prod"}, "other": {"env": "prod"}},
+		},
+		{
+			name:     "sets are not pruned without the prune option",
+			existing: []runtime.Object{testSet("managed", DefaultManagedBy, LabelSet{"env": "prod"})},
+			sets:     NodepoolLabelSets{},
+			result:   SyncResult{},
+			remaining: NodepoolLabelSets{
+				"managed": {"env": "prod"},
+			},
+		},
+		{
+			name:      "sets missing from a stale list are reported as updated",
+			existing:  []runtime.Object{testSet("pool", DefaultManagedBy, LabelSet{"env": "dev"})},
+			sets:      NodepoolLabelSets{"pool": {"env": "prod"}},
+			staleList: true,
+			result: SyncResult{
+				Updated: []string{"pool"},
+			},
+			remaining: NodepoolLabelSets{"pool": {"env": "prod"}},
+		},
+		{
+			name:     "unlabeled sets are adopted",
+			existing: []runtime.Object{testSet("pool", "", LabelSet{"env": "prod"})},
+			sets:     NodepoolLabelSets{"pool": {"env": "prod"}},
+			result: SyncResult{
+				Updated: []string{"pool"},
+			},
+			remaining: NodepoolLabelSets{"pool": {"env": "prod"}},
+			managed:   []string{"pool"},
+		},
+		{
+			name:     "empty unlabeled sets are deleted",
+			existing: []runtime.Object{testSet("pool", "", LabelSet{"env": "prod"})},
+			sets:     NodepoolLabelSets{"pool": {}},
+			result: SyncResult{
+				Deleted: []string{"pool"},
+			},
+			remaining: NodepoolLabelSets{},
+		},
+		{
+			name:      "sets of other systems are not updated",
+			existing:  []runtime.Object{testSet("pool", testOtherManager, LabelSet{"env": "dev"})},
+			sets:      NodepoolLabelSets{"pool": {"env": "prod"}},
+			failed:    true,
+			remaining: NodepoolLabelSets{"pool": {"env": "dev"}},
+		},
+		{
+			name:      "sets of other systems missing from a stale list are not updated",
+			existing:  []runtime.Object{testSet("pool", testOtherManager, LabelSet{"env": "dev"})},
+			sets:      NodepoolLabelSets{"pool": {"env": "prod"}},
+			staleList: true,
+			failed:    true,
+			remaining: NodepoolLabelSets{"pool": {"env": "dev"}},
+		},
+		{
+			name:      "sets of other systems are not deleted",
+			existing:  []runtime.Object{testSet("pool", testOtherManager, LabelSet{"env": "dev"})},
+			sets:      NodepoolLabelSets{"pool": {}},
+			failed:    true,
+			remaining: NodepoolLabelSets{"pool": {"env": "dev"}},
+		},
+		{
+			name:     "sets of other systems are adopted with the adopt option",
+			existing: []runtime.Object{testSet("pool", testOtherManager, LabelSet{"env": "prod"})},
+			sets:     NodepoolLabelSets{"pool": {"env": "prod"}},
+			options:  SyncOptions{WriteOptions: WriteOptions{Adopt: true}},
+			result: SyncResult{
+				Updated: []string{"pool"},
+			},
+			remaining: NodepoolLabelSets{"pool": {"env": "prod"}},
+			managed:   []string{"pool"},
+		},
+		{
+			name:     "created sets are managed",
+			existing: []runtime.Object{testSet("other", testOtherManager, LabelSet{"env": "prod"})},
+			sets:     NodepoolLabelSets{"pool": {"env": "prod"}},
+			result: SyncResult{
+				Created: []string{"pool"},
+			},
+			remaining: NodepoolLabelSets{"pool": {"env": "prod"}, "other": {"env": "prod"}},
+			managed:   []string{"pool"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(test.existing...)
+			m := NewManager(client, testNamespace)
+
+			if test.staleList {
+				listed := false
+				client.PrependReactor("list", "nodepoollabelsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					if listed {
+						return false, nil, nil
+					}
+					listed = true
+					return true, &v1alpha1.NodePoolLabelSetList{}, nil
+				})
+			}
+
+			result, err := m.Sync(context.Background(), test.sets, test.options)
+			if test.failed {
+				if !errors.Is(err, ErrNotManaged) {
+					t.Fatalf("expected ErrNotManaged, got %v", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := test.result
+			for _, names := range []*[]string{&expected.Created, &expected.Updated, &expected.Deleted, &expected.Unchanged} {
+				if *names == nil {
+					*names = []string{}
+				}
+			}
+			if !reflect.DeepEqual(*result, expected) {
+				t.Errorf("expected result %+v, got %+v", expected, *result)
+			}
+
+			remaining, err := m.GetAll(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(remaining, test.remaining) {
+				t.Errorf("expected sets %v, got %v", test.remaining, remaining)
+			}
+
+			for _, name := range test.managed {
+				npls, err := client.LabelsV1alpha1().NodePoolLabelSets(testNamespace).Get(context.Background(), name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if npls.Labels[ManagedByLabel] != DefaultManagedBy {
+					t.Errorf("expected %s to be managed, got labels %v", name, npls.Labels)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import "emperror.dev/errors"
+
+type Config struct {
+	// Enabled turns on the mutating admission webhook for node creation
+	Enabled bool `mapstructure:"enabled"`
+	// ListenAddress is the address the webhook HTTPS server listens on
+	ListenAddress string `mapstructure:"listenAddress"`
+	// Path is the URL path where admission reviews are served
+	Path string `mapstructure:"path"`
+	// ConversionPath is the URL path where NodePoolLabelSet conversion reviews are served
+	ConversionPath string `mapstructure:"conversionPath"`
+	// CertFile is the path of the TLS certificate
+	CertFile string `mapstructure:"certFile"`
+	// KeyFile is the path of the TLS private key
+	KeyFile string `mapstructure:"keyFile"`
+}
+
+// Validate checks that the configuration is valid.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.ListenAddress == "" {
+		return errors.New("listen address must not be empty")
+	}
+
+	if c.Path == "" {
+		return errors.New("path must not be empty")
+	}
+
+	if c.ConversionPath == "" {
+		return errors.New("conversion path must not be empty")
+	}
+
+	if c.ConversionPath == c.Path {
+		return errors.New("conversion path must differ from path")
+	}
+
+	if c.CertFile == "" || c.KeyFile == "" {
+		return errors.New("cert and key files must be set")
+	}
+
+	return nil
+}
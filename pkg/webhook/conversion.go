@@ -0,0 +1,143 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1alpha1"
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/apis/nodepoollabelset/v1beta1"
+)
+
+// conversionReview mirrors the apiextensions.k8s.io/v1 ConversionReview
+type conversionReview struct {
+	meta_v1.TypeMeta `json:",inline"`
+	Request          *conversionRequest  `json:"request,omitempty"`
+	Response         *conversionResponse `json:"response,omitempty"`
+}
+
+type conversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+type conversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           meta_v1.Status         `json:"result"`
+}
+
+func (w *Webhook) handleConversion(c *gin.Context) {
+	var review conversionReview
+	if err := c.ShouldBindJSON(&review); err != nil {
+		w.errorHandler.Handle(errors.WrapIf(err, "could not decode conversion review"))
+		c.String(http.StatusBadRequest, "could not decode conversion review")
+		return
+	}
+
+	if review.Request == nil {
+		c.String(http.StatusBadRequest, "conversion review request is missing")
+		return
+	}
+
+	review.Response = w.convert(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	c.JSON(http.StatusOK, review)
+}
+
+// convert converts every object of the request, a single failure fails the
+// whole request since the API server can't use partial results
+func (w *Webhook) convert(request *conversionRequest) *conversionResponse {
+	response := &conversionResponse{
+		ConvertedObjects: make([]runtime.RawExtension, 0, len(request.Objects)),
+	}
+
+	for _, object := range request.Objects {
+		converted, err := convertObject(object.Raw, request.DesiredAPIVersion)
+		if err != nil {
+			w.errorHandler.Handle(err)
+			response.ConvertedObjects = nil
+			response.Result = meta_v1.Status{
+				Status:  meta_v1.StatusFailure,
+				Message: err.Error(),
+			}
+			return response
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	w.logger.WithFields(log.Fields{
+		"objects":           len(request.Objects),
+		"desiredAPIVersion": request.DesiredAPIVersion,
+	}).Debug("converted nodepoollabelsets")
+
+	response.Result = meta_v1.Status{
+		Status: meta_v1.StatusSuccess,
+	}
+
+	return response
+}
+
+func convertObject(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	var typeMeta meta_v1.TypeMeta
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, errors.WrapIf(err, "could not unmarshal object")
+	}
+
+	if typeMeta.Kind != "NodePoolLabelSet" {
+		return nil, errors.NewWithDetails("unsupported kind", "kind", typeMeta.Kind)
+	}
+
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+
+	switch {
+	case typeMeta.APIVersion == v1alpha1.SchemeGroupVersion.String() && desiredAPIVersion == v1beta1.SchemeGroupVersion.String():
+		var in v1alpha1.NodePoolLabelSet
+		if err := json.Unmarshal(raw, &in); err != nil {
+			return nil, errors.WrapIf(err, "could not unmarshal v1alpha1 object")
+		}
+		var out v1beta1.NodePoolLabelSet
+		if err := v1beta1.ConvertFromV1alpha1(&in, &out); err != nil {
+			return nil, errors.WrapIf(err, "could not convert object to v1beta1")
+		}
+		return json.Marshal(out)
+
+	case typeMeta.APIVersion == v1beta1.SchemeGroupVersion.String() && desiredAPIVersion == v1alpha1.SchemeGroupVersion.String():
+		var in v1beta1.NodePoolLabelSet
+		if err := json.Unmarshal(raw, &in); err != nil {
+			return nil, errors.WrapIf(err, "could not unmarshal v1beta1 object")
+		}
+		var out v1alpha1.NodePoolLabelSet
+		if err := v1beta1.ConvertToV1alpha1(&in, &out); err != nil {
+			return nil, errors.WrapIf(err, "could not convert object to v1alpha1")
+		}
+		return json.Marshal(out)
+	}
+
+	return nil, errors.NewWithDetails("unsupported conversion", "from", typeMeta.APIVersion, "to", desiredAPIVersion)
+}
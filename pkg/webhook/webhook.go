@@ -0,0 +1,208 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	admission_v1 "k8s.io/api/admission/v1"
+	api_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/nodepool-labels-operator/internal/platform/log"
+)
+
+const (
+	shutdownTimeout = 5 * time.Second
+)
+
+// NodeMutator sets the desired state on a node object before it gets persisted
+type NodeMutator interface {
+	MutateNode(node *api_v1.Node) error
+}
+
+// Webhook is a mutating admission webhook which labels nodes at registration
+// time, it also serves the conversion of NodePoolLabelSets between API versions
+type Webhook struct {
+	config  Config
+	mutator NodeMutator
+
+	logger       log.Logger
+	errorHandler emperror.Handler
+}
+
+type patchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// New gives back an initialized Webhook
+func New(config Config, mutator NodeMutator, logger log.Logger, errorHandler emperror.Handler) *Webhook {
+	return &Webhook{
+		config:  config,
+		mutator: mutator,
+
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// Run runs the webhook HTTPS server until the context is cancelled
+func (w *Webhook) Run(ctx context.Context) {
+	w.logger.WithFields(log.Fields{
+		"addr":           w.config.ListenAddress,
+		"path":           w.config.Path,
+		"conversionPath": w.config.ConversionPath,
+	}).Info("starting admission webhook https server")
+
+	r := gin.New()
+	r.POST(w.config.Path, w.handle)
+	r.POST(w.config.ConversionPath, w.handleConversion)
+
+	server := &http.Server{
+		Addr:    w.config.ListenAddress,
+		Handler: r,
+	}
+
+	go func() {
+		<-ctx.Done()
+		w.logger.Info("shutting down admission webhook https server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			w.errorHandler.Handle(errors.WrapIf(err, "could not shut down admission webhook server"))
+		}
+	}()
+
+	err := server.ListenAndServeTLS(w.config.CertFile, w.config.KeyFile)
+	if err != nil && err != http.ErrServerClosed {
+		w.errorHandler.Handle(errors.WrapIf(err, "could not run admission webhook server"))
+	}
+}
+
+func (w *Webhook) handle(c *gin.Context) {
+	var review admission_v1.AdmissionReview
+	if err := c.ShouldBindJSON(&review); err != nil {
+		w.errorHandler.Handle(errors.WrapIf(err, "could not decode admission review"))
+		c.String(http.StatusBadRequest, "could not decode admission review")
+		return
+	}
+
+	if review.Request == nil {
+		c.String(http.StatusBadRequest, "admission review request is missing")
+		return
+	}
+
+	review.Response = w.admit(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	c.JSON(http.StatusOK, review)
+}
+
+// admit always allows the request, failures only result in a missing patch
+// since the controller labels the node afterwards anyway
+func (w *Webhook) admit(request *admission_v1.AdmissionRequest) *admission_v1.AdmissionResponse {
+	response := &admission_v1.AdmissionResponse{
+		Allowed: true,
+	}
+
+	if request.Kind.Kind != "Node" || request.Operation != admission_v1.Create {
+		return response
+	}
+
+	var node api_v1.Node
+	if err := json.Unmarshal(request.Object.Raw, &node); err != nil {
+		w.errorHandler.Handle(errors.WrapIf(err, "could not unmarshal node object"))
+		return response
+	}
+
+	logger := w.logger.WithField("node", node.Name)
+
+	original := node.DeepCopy()
+	if err := w.mutator.MutateNode(&node); err != nil {
+		w.errorHandler.Handle(errors.WrapIfWithDetails(err, "could not mutate node", "node", node.Name))
+		return response
+	}
+
+	patch, err := createPatch(original, &node)
+	if err != nil {
+		w.errorHandler.Handle(errors.WrapIfWithDetails(err, "could not create patch", "node", node.Name))
+		return response
+	}
+
+	if patch == nil {
+		return response
+	}
+
+	logger.Debug("labeling node at admission")
+
+	patchType := admission_v1.PatchTypeJSONPatch
+	response.Patch = patch
+	response.PatchType = &patchType
+	response.Result = &meta_v1.Status{
+		Status: meta_v1.StatusSuccess,
+	}
+
+	return response
+}
+
+func createPatch(original *api_v1.Node, node *api_v1.Node) ([]byte, error) {
+	ops := make([]patchOperation, 0)
+
+	if !equalMaps(original.GetLabels(), node.GetLabels()) {
+		ops = append(ops, patchOperation{
+			Op:    "add",
+			Path:  "/metadata/labels",
+			Value: node.GetLabels(),
+		})
+	}
+
+	if !equalMaps(original.GetAnnotations(), node.GetAnnotations()) {
+		ops = append(ops, patchOperation{
+			Op:    "add",
+			Path:  "/metadata/annotations",
+			Value: node.GetAnnotations(),
+		})
+	}
+
+	if len(ops) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(ops)
+}
+
+func equalMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+
+	return true
+}